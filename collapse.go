@@ -0,0 +1,142 @@
+package hclogzerolog
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// WithCollapseRepeats collapses the identical consecutive messages of the family, the ones with the same
+// name, implied args, level, message and args. The first message of a run is written as usual,
+// the repeats are dropped and counted. Once the run ends, i.e. another message is logged,
+// or the interval elapses, the message is written once more with the number of the repeats
+// dropped in the "repeated" field:
+//
+//	{"level":"info","hclog_name":"memberlist","message":"Stream connection"}
+//	{"level":"info","hclog_name":"memberlist","repeated":41,"message":"Stream connection"}
+//
+// Zero interval writes the repeats when the run ends only, so call [Logger.Flush] before the program exits
+// not to lose the repeats of the last run.
+// The repeats are reported at the location of the first message of the run, see [WithLocation].
+// The messages are compared after [WithExclude] and [WithRateLimit], so the dropped ones don't break the runs.
+func WithCollapseRepeats(interval time.Duration) Option {
+	return func(c *config) {
+		c.collapser = &collapser{interval: interval}
+	}
+}
+
+// Flush writes the repeats of the last run collapsed with [WithCollapseRepeats], if any.
+// It's safe to call it any time, the run goes on and the next repeats are counted from zero.
+func (l *Logger) Flush() {
+	if l.config.collapser != nil {
+		l.config.collapser.flushLast()
+	}
+}
+
+// collapser tracks the last message of the family and the number of its repeats.
+type collapser struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	last *repeatRun
+}
+
+// repeatRun is the run of the identical messages.
+type repeatRun struct {
+	logger   *Logger
+	caller   string
+	level    hclog.Level
+	original hclog.Level
+	msg      string
+	args     []any
+	repeated int
+	// flush is the timer writing the repeats, nil if there are none
+	flush *time.Timer
+}
+
+// collapse reports whether the message starts a new run, so it has to be written.
+// The repeats of the previous run to be written before it are returned, if any.
+func (c *collapser) collapse(logger *Logger, caller string, level, original hclog.Level, msg string, args []any) (*repeatRun, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if last := c.last; last != nil && last.matches(logger, level, msg, args) {
+		last.repeated++
+
+		if last.flush == nil && c.interval > 0 {
+			last.flush = time.AfterFunc(c.interval, func() {
+				c.flush(last)
+			})
+		}
+
+		return nil, false
+	}
+
+	ended := c.last.end()
+	c.last = &repeatRun{logger: logger, caller: caller, level: level, original: original, msg: msg, args: slices.Clone(args)}
+
+	return ended, true
+}
+
+// flush writes the repeats of the run, if it's still the last one, keeping the run going.
+func (c *collapser) flush(run *repeatRun) {
+	c.mu.Lock()
+
+	if c.last != run {
+		c.mu.Unlock()
+
+		return
+	}
+
+	flushed := run.end()
+	c.last = &repeatRun{
+		logger: run.logger, caller: run.caller, level: run.level, original: run.original, msg: run.msg, args: run.args,
+	}
+
+	c.mu.Unlock()
+
+	flushed.write()
+}
+
+// flushLast writes the repeats of the last run, if any, keeping the run going.
+func (c *collapser) flushLast() {
+	c.mu.Lock()
+	last := c.last
+	c.mu.Unlock()
+
+	if last != nil {
+		c.flush(last)
+	}
+}
+
+// end stops the flush timer of the run and returns it, if it has any repeats.
+// The caller must hold the mutex of the collapser.
+func (r *repeatRun) end() *repeatRun {
+	if r == nil || r.repeated == 0 {
+		return nil
+	}
+
+	if r.flush != nil {
+		r.flush.Stop()
+	}
+
+	return r
+}
+
+func (r *repeatRun) matches(logger *Logger, level hclog.Level, msg string, args []any) bool {
+	return r.level == level && r.msg == msg && r.logger.name == logger.name &&
+		reflect.DeepEqual(r.logger.implied, logger.implied) && reflect.DeepEqual(r.args, args)
+}
+
+// write writes the message of the run with the number of the repeats.
+func (r *repeatRun) write() {
+	if r == nil {
+		return
+	}
+
+	args := append(slices.Clip(r.args), "repeated", r.repeated)
+	r.logger.write(0, r.caller, r.level, r.original, r.msg, args)
+}
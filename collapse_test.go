@@ -0,0 +1,180 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestWithCollapseRepeats(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(zerolog.New(buf), WithCollapseRepeats(0))
+	memberlist := root.Named("memberlist")
+
+	for range 3 {
+		memberlist.Info("Stream connection", "from", "10.0.0.2", "err", errors.New("EOF"))
+	}
+
+	memberlist.Info("Stream connection", "from", "10.0.0.3")
+	memberlist.With("from", "10.0.0.3").Info("Stream connection")
+	memberlist.With("from", "10.0.0.3").Info("Stream connection")
+	root.Named("serf").Info("Stream connection", "from", "10.0.0.3")
+	memberlist.Warn("Stream connection", "from", "10.0.0.3")
+
+	msgs := decodeLines(t, buf)
+
+	wants := []struct {
+		name     string
+		from     string
+		repeated any
+	}{
+		{"memberlist", "10.0.0.2", nil},
+		{"memberlist", "10.0.0.2", float64(2)},
+		{"memberlist", "10.0.0.3", nil},
+		{"memberlist", "10.0.0.3", nil},
+		{"memberlist", "10.0.0.3", float64(1)},
+		{"serf", "10.0.0.3", nil},
+	}
+
+	if len(msgs) != len(wants)+1 {
+		t.Fatalf("expected %d messages, got: %v", len(wants)+1, buf.lines())
+	}
+
+	for i, want := range wants {
+		msg := msgs[i]
+
+		if msg[DefaultNameField] != want.name || msg["from"] != want.from || msg["repeated"] != want.repeated {
+			t.Errorf("message %d: expected %+v, got %v", i, want, msg)
+		}
+	}
+
+	if msgs[len(wants)]["level"] != "warn" {
+		t.Errorf("expected the last message to be a warning, got %v", msgs[len(wants)])
+	}
+}
+
+func TestWithCollapseRepeatsInterval(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(zerolog.New(buf), WithCollapseRepeats(20*time.Millisecond))
+
+	for range 4 {
+		root.Info(messageToLog)
+	}
+
+	waitFor(t, func() bool { return len(buf.lines()) == 2 })
+
+	if repeated := decodeLines(t, buf)[1]["repeated"]; repeated != float64(3) {
+		t.Errorf("expected 3 repeats, got %v", repeated)
+	}
+
+	root.Info(messageToLog)
+	root.Info(customFieldValue)
+
+	if lines := buf.lines(); len(lines) != 4 {
+		t.Fatalf("expected the run to go on after the flush, got: %v", lines)
+	}
+
+	if repeated := decodeLines(t, buf)[2]["repeated"]; repeated != float64(1) {
+		t.Errorf("expected 1 repeat, got %v", repeated)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if lines := buf.lines(); len(lines) != 4 {
+		t.Errorf("expected nothing to be flushed with no repeats, got: %v", lines)
+	}
+}
+
+func TestLoggerFlush(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(zerolog.New(buf), WithCollapseRepeats(0))
+
+	root.Flush()
+
+	for range 5 {
+		root.Info(messageToLog)
+	}
+
+	if lines := buf.lines(); len(lines) != 1 {
+		t.Fatalf("expected the repeats to be held back, got: %v", lines)
+	}
+
+	root.named("raft").Flush()
+	root.Flush()
+
+	msgs := decodeLines(t, buf)
+	if len(msgs) != 2 || msgs[1]["repeated"] != float64(4) {
+		t.Fatalf("expected 4 repeats to be flushed once, got: %v", buf.lines())
+	}
+
+	root.Info(messageToLog)
+	root.Info(customFieldValue)
+
+	if msgs := decodeLines(t, buf); len(msgs) != 4 || msgs[2]["repeated"] != float64(1) {
+		t.Errorf("expected the run to go on after the flush, got: %v", buf.lines())
+	}
+
+	New(zerolog.Nop()).Flush()
+}
+
+func TestWithCollapseRepeatsLocation(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(zerolog.New(buf), WithLocation(), WithCollapseRepeats(0))
+
+	_, file, line, _ := runtime.Caller(0)
+	for range 3 {
+		root.Info(messageToLog)
+	}
+
+	root.Flush()
+
+	msgs := decodeLines(t, buf)
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got: %v", buf.lines())
+	}
+
+	assertCaller(t, msgs[0], file, line+2)
+	assertCaller(t, msgs[1], file, line+2)
+}
+
+func TestWithCollapseRepeatsLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	root := NewWithOptions(zerolog.New(buf), WithLevel(hclog.Info), WithCollapseRepeats(0))
+
+	root.Info(messageToLog)
+	root.Debug(customFieldValue)
+	root.Info(messageToLog)
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 || strings.Contains(buf.String(), "repeated") {
+		t.Errorf("expected the messages below the level not to break the run, got: %s", buf.String())
+	}
+}
+
+func TestCollapseRepeatsRace(t *testing.T) {
+	root := NewWithOptions(zerolog.New(io.Discard), WithCollapseRepeats(time.Millisecond))
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 100 {
+				root.Info(messageToLog, "attempt", strconv.Itoa((i+j)%2))
+			}
+		}()
+	}
+
+	wg.Wait()
+}
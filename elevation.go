@@ -0,0 +1,124 @@
+package hclogzerolog
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// elevationStack is the stack of the temporary levels applied on top of each other.
+//
+// Every elevation remembers the level to restore once it ends. If it ends while the elevations
+// applied after it are still active, the one right above inherits the level to restore,
+// so once all of them end, in any order, the level they started from is restored.
+type elevationStack[T any] struct {
+	mu    sync.Mutex
+	stack []*elevation[T]
+}
+
+type elevation[T any] struct {
+	previous T
+}
+
+// elevate applies the level with set, remembering the one returned by get, and returns the function
+// ending the elevation. The elevation ends by itself once the duration elapses.
+func (s *elevationStack[T]) elevate(get func() T, set func(T), level T, duration time.Duration) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elevated := &elevation[T]{previous: get()}
+	s.stack = append(s.stack, elevated)
+	set(level)
+
+	var once sync.Once
+
+	end := func() {
+		once.Do(func() {
+			s.end(elevated, set)
+		})
+	}
+
+	timer := time.AfterFunc(duration, end)
+
+	return func() {
+		timer.Stop()
+		end()
+	}
+}
+
+func (s *elevationStack[T]) end(elevated *elevation[T], set func(T)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.Index(s.stack, elevated)
+	if i < 0 {
+		return
+	}
+
+	if i == len(s.stack)-1 {
+		set(elevated.previous)
+	} else {
+		s.stack[i+1].previous = elevated.previous
+	}
+
+	s.stack = slices.Delete(s.stack, i, i+1)
+}
+
+// nameRule is the level rule of a prefix, ok is false if the prefix has no rule.
+type nameRule struct {
+	level hclog.Level
+	ok    bool
+}
+
+// SetLevelFor sets the level of the logger, as [Logger.SetLevel] does, for the duration.
+// Once it elapses or the returned function is called, whatever comes first,
+// the level the logger had before is restored, even if it's been changed with [Logger.SetLevel] meanwhile.
+//
+// The elevations may overlap: an elevation ending while the ones set after it are still active
+// doesn't change the level, but passes the level to restore to them. So the level is restored
+// once all of them end, in any order.
+//
+//	cancel := logger.SetLevelFor(hclog.Debug, 10*time.Minute)
+//	defer cancel()
+func (l *Logger) SetLevelFor(level hclog.Level, duration time.Duration) func() {
+	mapped, ok := l.mapping.toZerolog(level)
+	if !ok {
+		l.unknownLevel(level)
+
+		return func() {}
+	}
+
+	return l.level.elevations.elevate(l.level.get, l.level.set, mapped, duration)
+}
+
+// SetNameLevelFor sets the level of the loggers named prefix or descending from it,
+// as [Logger.SetNameLevel] does, for the duration. Once it elapses or the returned function is called,
+// whatever comes first, the rule the prefix had before is restored, or removed if there was none.
+// Overlapping elevations of the same prefix are resolved like the ones of [Logger.SetLevelFor].
+func (l *Logger) SetNameLevelFor(prefix string, level hclog.Level, duration time.Duration) func() {
+	if _, ok := l.mapping.toZerolog(level); !ok {
+		l.unknownLevel(level)
+
+		return func() {}
+	}
+
+	rules := l.config.nameLevels
+
+	get := func() nameRule {
+		level, ok := rules.get(prefix)
+
+		return nameRule{level, ok}
+	}
+
+	set := func(rule nameRule) {
+		if rule.ok {
+			rules.set(prefix, rule.level)
+		} else {
+			rules.reset(prefix)
+		}
+	}
+
+	return rules.elevationsOf(prefix).elevate(get, set, nameRule{level, true}, duration)
+}
@@ -0,0 +1,143 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestSetLevelFor(t *testing.T) {
+	t.Run("reverts when the duration elapses", func(t *testing.T) {
+		root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevel(hclog.Info))
+		raft := root.Named("raft")
+
+		raft.(*Logger).SetLevelFor(hclog.Debug, 20*time.Millisecond)
+
+		if root.GetLevel() != hclog.Debug {
+			t.Errorf("expected the family to follow the level, got %v", root.GetLevel())
+		}
+
+		waitFor(t, func() bool { return raft.GetLevel() == hclog.Info })
+	})
+
+	t.Run("reverts when canceled", func(t *testing.T) {
+		root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevel(hclog.Info))
+
+		cancel := root.SetLevelFor(hclog.Trace, time.Hour)
+		root.SetLevel(hclog.Error)
+		cancel()
+		cancel()
+
+		if root.GetLevel() != hclog.Info {
+			t.Errorf("expected level to be %v, got %v", hclog.Info, root.GetLevel())
+		}
+	})
+
+	t.Run("unknown level", func(t *testing.T) {
+		root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevel(hclog.Info))
+
+		root.SetLevelFor(hclog.Level(999), time.Hour)()
+
+		if root.GetLevel() != hclog.Info {
+			t.Errorf("expected level to be %v, got %v", hclog.Info, root.GetLevel())
+		}
+	})
+}
+
+func TestSetLevelForOverlapping(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []int
+		wants []hclog.Level
+	}{
+		{"last first", []int{1, 0}, []hclog.Level{hclog.Debug, hclog.Info}},
+		{"first first", []int{0, 1}, []hclog.Level{hclog.Trace, hclog.Info}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevel(hclog.Info))
+
+			cancels := []func(){
+				root.SetLevelFor(hclog.Debug, time.Hour),
+				root.SetLevelFor(hclog.Trace, time.Hour),
+			}
+
+			for i, elevation := range tt.order {
+				cancels[elevation]()
+
+				if root.GetLevel() != tt.wants[i] {
+					t.Errorf("step %d: expected level to be %v, got %v", i, tt.wants[i], root.GetLevel())
+				}
+			}
+		})
+	}
+}
+
+func TestSetNameLevelFor(t *testing.T) {
+	root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevel(hclog.Info), WithNameLevel("raft", hclog.Warn))
+	raft := root.Named("raft")
+	raftNet := raft.Named("net")
+
+	cancel := root.SetNameLevelFor("raft", hclog.Debug, time.Hour)
+	root.SetNameLevelFor("raft.net", hclog.Trace, 20*time.Millisecond)
+
+	if raft.GetLevel() != hclog.Debug || raftNet.GetLevel() != hclog.Trace {
+		t.Errorf("expected levels to be elevated, got %v and %v", raft.GetLevel(), raftNet.GetLevel())
+	}
+
+	waitFor(t, func() bool { return raftNet.GetLevel() == hclog.Debug })
+
+	if _, ok := root.config.nameLevels.get("raft.net"); ok {
+		t.Errorf("expected the rule to be removed")
+	}
+
+	cancel()
+
+	if raft.GetLevel() != hclog.Warn {
+		t.Errorf("expected the previous rule to be restored, got %v", raft.GetLevel())
+	}
+
+	if root.GetLevel() != hclog.Info {
+		t.Errorf("expected unnamed logger to keep its level, got %v", root.GetLevel())
+	}
+}
+
+func TestSetLevelForRace(t *testing.T) {
+	root := NewWithOptions(zerolog.New(io.Discard), WithLevel(hclog.Info))
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 50 {
+				cancel := root.SetLevelFor(hclog.Level(1+(i+j)%5), time.Duration(j%3)*time.Millisecond)
+				cancelName := root.SetNameLevelFor("raft", hclog.Level(1+j%5), time.Duration(j%2)*time.Millisecond)
+
+				root.Named("raft").Info(messageToLog)
+
+				cancel()
+				cancelName()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if root.GetLevel() != hclog.Info {
+		t.Errorf("expected level to be restored, got %v", root.GetLevel())
+	}
+
+	if _, ok := root.config.nameLevels.get("raft"); ok {
+		t.Errorf("expected the rule to be removed")
+	}
+}
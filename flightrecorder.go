@@ -0,0 +1,176 @@
+package hclogzerolog
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+// FlightRecorderField is the field marking the messages written by the flight recorder, see [WithFlightRecorder].
+const FlightRecorderField = "flight_recorder"
+
+// RecordedAtField is the field holding the time the message written by the flight recorder was logged at.
+const RecordedAtField = "recorded_at"
+
+// DefaultFlightRecorderNames is the number of the names the flight recorder keeps the messages for
+// unless [FlightRecorder] Names is set.
+const DefaultFlightRecorderNames = 256
+
+// FlightRecorder configures [WithFlightRecorder].
+type FlightRecorder struct {
+	// Size is the number of the latest messages kept per name, zero or less disables the recorder.
+	Size int
+	// Names is the number of the names the messages are kept for, [DefaultFlightRecorderNames] if zero or less.
+	// Once there are more, the messages of the name recorded least recently are dropped.
+	Names int
+	// Trigger reports whether the message flushes the messages kept.
+	// By default, the messages at [hclog.Error] do.
+	Trigger func(level hclog.Level, msg string, args ...any) bool
+}
+
+// WithFlightRecorder keeps the latest messages dropped by the level check, e.g. Trace and Debug ones
+// for the logger at Info, in a ring buffer per name. Once a message the Trigger returns true for
+// is written by a logger of the name, the messages kept for the name are written right before it,
+// marked with the [FlightRecorderField] and the [RecordedAtField]:
+//
+//	{"level":"debug","hclog_name":"raft","flight_recorder":true,"recorded_at":"2024-05-01T10:00:00Z","message":"sending heartbeat"}
+//	{"level":"error","hclog_name":"raft","message":"failed to heartbeat to"}
+//
+// So the memory used is bounded by Size messages for each of at most Names names.
+// The messages kept don't keep the loggers which logged them alive.
+// With [WithLocation], the messages are reported at the location they were logged at.
+// The messages excluded with [WithExclude] aren't kept.
+func WithFlightRecorder(recorder FlightRecorder) Option {
+	return func(c *config) {
+		if recorder.Size <= 0 {
+			return
+		}
+
+		names := recorder.Names
+		if names <= 0 {
+			names = DefaultFlightRecorderNames
+		}
+
+		trigger := recorder.Trigger
+		if trigger == nil {
+			trigger = func(level hclog.Level, _ string, _ ...any) bool {
+				return level == hclog.Error
+			}
+		}
+
+		c.recorder = &flightRecorder{size: recorder.Size, names: names, trigger: trigger}
+	}
+}
+
+// flightRecorder keeps the ring buffers of the names of a family,
+// the rings are ordered from the most recently recorded one to the least recently recorded one.
+type flightRecorder struct {
+	size    int
+	names   int
+	trigger func(level hclog.Level, msg string, args ...any) bool
+
+	mu    sync.Mutex
+	rings map[string]*list.Element
+	order list.List
+}
+
+// recordedEvent is the message kept to be written later. It holds the context and the implied args
+// of the logger rather than the logger itself, the rest is the same for all the loggers of the name.
+type recordedEvent struct {
+	context    zerolog.Logger
+	implied    []any
+	caller     string
+	level      hclog.Level
+	original   hclog.Level
+	msg        string
+	args       []any
+	recordedAt time.Time
+}
+
+// eventRing is the ring buffer of the messages of a name, next is the position of the oldest one once it's full.
+type eventRing struct {
+	name   string
+	events []recordedEvent
+	next   int
+}
+
+// record keeps the message in the ring buffer of the name of the logger,
+// dropping the least recently recorded ring if there are too many.
+func (r *flightRecorder) record(logger *Logger, caller string, level, original hclog.Level, msg string, args []any) {
+	event := recordedEvent{logger.logger, logger.implied, caller, level, original, msg, slices.Clone(args), time.Now()}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.rings == nil {
+		r.rings = make(map[string]*list.Element)
+	}
+
+	element, ok := r.rings[logger.name]
+	if ok {
+		r.order.MoveToFront(element)
+	} else {
+		if r.order.Len() >= r.names {
+			oldest := r.order.Back()
+			delete(r.rings, r.order.Remove(oldest).(*eventRing).name)
+		}
+
+		element = r.order.PushFront(&eventRing{name: logger.name, events: make([]recordedEvent, 0, r.size)})
+		r.rings[logger.name] = element
+	}
+
+	element.Value.(*eventRing).push(event, r.size)
+}
+
+// flush writes the messages kept for the name of the logger, the oldest first, and forgets them.
+// They are written on behalf of the loggers which logged them, rebuilt from the events.
+func (r *flightRecorder) flush(logger *Logger) {
+	r.mu.Lock()
+
+	element, ok := r.rings[logger.name]
+	if ok {
+		delete(r.rings, logger.name)
+		r.order.Remove(element)
+	}
+
+	r.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	for _, event := range element.Value.(*eventRing).drain() {
+		recorded := &Logger{
+			base:    logger.base,
+			logger:  event.context,
+			config:  logger.config,
+			name:    logger.name,
+			implied: event.implied,
+			mapping: logger.mapping,
+			sampler: logger.sampler,
+		}
+
+		args := append(slices.Clip(event.args), FlightRecorderField, true, RecordedAtField, event.recordedAt)
+		recorded.write(0, event.caller, event.level, event.original, event.msg, args)
+	}
+}
+
+func (r *eventRing) push(event recordedEvent, size int) {
+	if len(r.events) < size {
+		r.events = append(r.events, event)
+
+		return
+	}
+
+	r.events[r.next] = event
+	r.next = (r.next + 1) % size
+}
+
+// drain returns the messages, the oldest first.
+func (r *eventRing) drain() []recordedEvent {
+	return slices.Concat(r.events[r.next:], r.events[:r.next])
+}
@@ -0,0 +1,238 @@
+package hclogzerolog
+
+import (
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestWithFlightRecorder(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(zerolog.New(buf), WithLevel(hclog.Info), WithFlightRecorder(FlightRecorder{Size: 3}))
+	raft := root.Named("raft")
+
+	for i := range 5 {
+		raft.Debug("sending heartbeat", "attempt", i)
+	}
+
+	raft.Trace("appending entries")
+	root.Named("serf").Debug("probing")
+	raft.Info(messageToLog)
+	raft.Error("failed to heartbeat to")
+
+	msgs := decodeLines(t, buf)
+
+	wants := []struct {
+		level   string
+		message string
+		marked  bool
+	}{
+		{"info", messageToLog, false},
+		{"debug", "sending heartbeat", true},
+		{"debug", "sending heartbeat", true},
+		{"trace", "appending entries", true},
+		{"error", "failed to heartbeat to", false},
+	}
+
+	if len(msgs) != len(wants) {
+		t.Fatalf("expected %d messages, got: %v", len(wants), buf.lines())
+	}
+
+	for i, want := range wants {
+		msg := msgs[i]
+
+		if msg["level"] != want.level || msg["message"] != want.message || msg[DefaultNameField] != "raft" {
+			t.Errorf("message %d: expected %+v, got %v", i, want, msg)
+		}
+
+		if _, marked := msg[FlightRecorderField]; marked != want.marked {
+			t.Errorf("message %d: expected to be marked %v, got %v", i, want.marked, msg)
+		}
+
+		if _, ok := msg[RecordedAtField]; ok != want.marked {
+			t.Errorf("message %d: expected %q to be set %v, got %v", i, RecordedAtField, want.marked, msg)
+		}
+	}
+
+	if msgs[1]["attempt"] != float64(3) || msgs[2]["attempt"] != float64(4) {
+		t.Errorf("expected the latest messages to be kept, the oldest first, got %v and %v", msgs[1], msgs[2])
+	}
+
+	raft.Error("failed to heartbeat to")
+
+	if lines := buf.lines(); len(lines) != len(wants)+1 {
+		t.Errorf("expected the messages to be flushed once, got: %v", lines)
+	}
+}
+
+func TestWithFlightRecorderTrigger(t *testing.T) {
+	buf := &syncBuffer{}
+	trigger := func(_ hclog.Level, msg string, _ ...any) bool {
+		return strings.HasPrefix(msg, "failed")
+	}
+	root := NewWithOptions(
+		zerolog.New(buf),
+		WithLevel(hclog.Info),
+		WithFlightRecorder(FlightRecorder{Size: 10, Trigger: trigger}),
+		WithExclude(func(_ hclog.Level, msg string, _ ...any) bool { return msg == customFieldValue }),
+	)
+
+	root.Debug(messageToLog)
+	root.Debug(customFieldValue)
+	root.Error("shutting down")
+
+	if lines := buf.lines(); len(lines) != 1 {
+		t.Fatalf("expected no messages to be flushed, got: %v", lines)
+	}
+
+	root.Warn("failed to contact")
+
+	msgs := decodeLines(t, buf)
+
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages, got: %v", buf.lines())
+	}
+
+	if msgs[1]["message"] != messageToLog || msgs[1][FlightRecorderField] != true {
+		t.Errorf("expected the message kept to be flushed before the trigger, got %v", msgs[1])
+	}
+}
+
+func TestWithFlightRecorderDisabled(t *testing.T) {
+	root := NewWithOptions(zerolog.New(io.Discard), WithFlightRecorder(FlightRecorder{Size: 0}))
+
+	if root.config.recorder != nil {
+		t.Errorf("expected zero size to disable the recorder")
+	}
+}
+
+func TestEventRing(t *testing.T) {
+	ring := &eventRing{}
+
+	for i := range 7 {
+		ring.push(recordedEvent{msg: strconv.Itoa(i)}, 3)
+	}
+
+	if len(ring.events) != 3 {
+		t.Fatalf("expected the ring to be bounded to 3 messages, got %d", len(ring.events))
+	}
+
+	var msgs []string
+	for _, event := range ring.drain() {
+		msgs = append(msgs, event.msg)
+	}
+
+	if got := strings.Join(msgs, ","); got != "4,5,6" {
+		t.Errorf("expected the messages to be %q, got %q", "4,5,6", got)
+	}
+}
+
+func TestFlightRecorderRace(t *testing.T) {
+	root := NewWithOptions(zerolog.New(io.Discard), WithLevel(hclog.Info), WithFlightRecorder(FlightRecorder{Size: 8}))
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			logger := root.Named(strconv.Itoa(i % 3))
+
+			for j := range 100 {
+				logger.Debug(messageToLog, "attempt", j)
+
+				if j%10 == 0 {
+					logger.Error(messageToLog, "at", time.Now())
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	recorder := root.config.recorder
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	for name, element := range recorder.rings {
+		if events := element.Value.(*eventRing).events; len(events) > recorder.size {
+			t.Errorf("expected the ring of %q to be bounded to %d messages, got %d", name, recorder.size, len(events))
+		}
+	}
+}
+
+func TestFlightRecorderNames(t *testing.T) {
+	buf := &syncBuffer{}
+	registry := NewRegistry()
+	root := NewWithOptions(
+		zerolog.New(buf),
+		WithLevel(hclog.Info),
+		WithRegistry(registry),
+		WithFlightRecorder(FlightRecorder{Size: 2, Names: 10}),
+	)
+
+	for i := range 100 {
+		root.Named("n"+strconv.Itoa(i)).With("attempt", i).Debug(messageToLog)
+	}
+
+	runtime.GC()
+
+	if infos := registry.Snapshot(); len(infos) != 0 {
+		t.Errorf("expected the loggers of the messages kept to be collected, got %v", infos)
+	}
+
+	recorder := root.config.recorder
+
+	recorder.mu.Lock()
+	rings := len(recorder.rings)
+	recorder.mu.Unlock()
+
+	if rings != 10 {
+		t.Fatalf("expected the rings to be bounded to 10 names, got %d", rings)
+	}
+
+	root.Named("n0").Error(messageToLog)
+
+	if lines := buf.lines(); len(lines) != 1 {
+		t.Errorf("expected the messages of the least recently recorded names to be dropped, got: %v", lines)
+	}
+
+	root.Named("n99").Error(messageToLog)
+
+	msgs := decodeLines(t, buf)
+	if len(msgs) != 3 || msgs[1]["attempt"] != float64(99) || msgs[1][FlightRecorderField] != true {
+		t.Errorf("expected the message of the collected logger to be flushed with its implied args, got: %v", buf.lines())
+	}
+}
+
+func TestWithFlightRecorderLocation(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(
+		zerolog.New(buf),
+		WithLevel(hclog.Info),
+		WithLocation(),
+		WithFlightRecorder(FlightRecorder{Size: 10}),
+	)
+
+	_, file, line, _ := runtime.Caller(0)
+	root.Debug(messageToLog)
+	root.Error(messageToLog)
+
+	msgs := decodeLines(t, buf)
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got: %v", buf.lines())
+	}
+
+	assertCaller(t, msgs[0], file, line+1)
+	assertCaller(t, msgs[1], file, line+2)
+}
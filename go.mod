@@ -3,7 +3,7 @@ module github.com/weastur/hclog-zerolog
 go 1.24.1
 
 require (
-	github.com/hashicorp/go-hclog v1.0.0
+	github.com/hashicorp/go-hclog v1.6.3
 	github.com/rs/zerolog v1.25.0
 )
 
@@ -0,0 +1,240 @@
+package hclogzerolog
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// maxLevelRequestSize limits the body of the requests to [LevelHandler].
+const maxLevelRequestSize = 1 << 16
+
+// LevelHandler is an [http.Handler] to inspect and change the levels of a family of loggers at runtime.
+//
+// GET lists the live named loggers of the family recorded by its [Registry], see [WithRegistry],
+// with their effective levels and the level rules set by name prefix, see [Logger.SetNameLevel]:
+//
+//	{"loggers": [{"name": "raft", "level": "info"}], "levels": [{"prefix": "raft", "level": "trace"}]}
+//
+// GET with the prefix query parameter returns the rule of the prefix, if any.
+//
+// PUT sets the level of the prefix, the ttl (a [time.ParseDuration] string) is optional:
+//
+//	{"prefix": "raft", "level": "trace", "ttl": "10m"}
+//
+// Once the ttl elapses, the rule the prefix had before is restored.
+// Both [hclog] and [zerolog] level names are accepted, see [ParseLevelSpec].
+//
+// DELETE with the prefix query parameter removes the rule of the prefix.
+//
+// The handler doesn't do any authentication, so it shouldn't be exposed publicly.
+type LevelHandler struct {
+	logger *Logger
+
+	mu      sync.Mutex
+	reverts map[string]*levelRevert
+}
+
+// levelRevert is the pending restoration of the rule of a prefix set with ttl.
+type levelRevert struct {
+	cancel    func()
+	expiresAt time.Time
+}
+
+type loggerLevel struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+type prefixLevel struct {
+	Prefix    string     `json:"prefix"`
+	Level     string     `json:"level"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type levelsResponse struct {
+	Loggers []loggerLevel `json:"loggers"`
+	Levels  []prefixLevel `json:"levels"`
+}
+
+type levelRequest struct {
+	Prefix string `json:"prefix"`
+	Level  string `json:"level"`
+	TTL    string `json:"ttl"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewLevelHandler creates an instance of [LevelHandler] managing the levels of the family of the logger.
+// The loggers are listed only if the family has a [Registry], it doesn't matter when the handler is created.
+//
+//	logger := hclogzerolog.NewWithOptions(log.Logger, hclogzerolog.WithRegistry(hclogzerolog.NewRegistry()))
+//	...
+//	mux.Handle("/debug/levels", hclogzerolog.NewLevelHandler(logger))
+func NewLevelHandler(logger *Logger) *LevelHandler {
+	return &LevelHandler{logger: logger, reverts: make(map[string]*levelRevert)}
+}
+
+func (h *LevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if r.URL.Query().Has("prefix") {
+			h.getLevel(w, r.URL.Query().Get("prefix"))
+		} else {
+			h.listLevels(w)
+		}
+	case http.MethodPut:
+		h.putLevel(w, r)
+	case http.MethodDelete:
+		if !r.URL.Query().Has("prefix") {
+			writeJSON(w, http.StatusBadRequest, errorResponse{"prefix query parameter is required"})
+
+			return
+		}
+
+		h.deleteLevel(w, r.URL.Query().Get("prefix"))
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"method not allowed"})
+	}
+}
+
+func (h *LevelHandler) listLevels(w http.ResponseWriter) {
+	response := levelsResponse{Loggers: []loggerLevel{}, Levels: []prefixLevel{}}
+
+	if registry := h.logger.config.registry; registry != nil {
+		loggers := registry.latest(h.logger.config)
+
+		for _, name := range slices.Sorted(maps.Keys(loggers)) {
+			response.Loggers = append(response.Loggers, loggerLevel{Name: name, Level: loggers[name].GetLevel().String()})
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rules := h.logger.config.nameLevels.snapshot()
+	for _, prefix := range slices.Sorted(maps.Keys(rules)) {
+		response.Levels = append(response.Levels, h.prefixLevel(prefix, rules[prefix]))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *LevelHandler) getLevel(w http.ResponseWriter, prefix string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	level, ok := h.logger.config.nameLevels.get(prefix)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{fmt.Sprintf("no level set for %q", prefix)})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.prefixLevel(prefix, level))
+}
+
+func (h *LevelHandler) putLevel(w http.ResponseWriter, r *http.Request) {
+	var request levelRequest
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLevelRequestSize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("invalid request: %v", err)})
+
+		return
+	}
+
+	level, ok := parseLevel(request.Level)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("unknown level %q", request.Level)})
+
+		return
+	}
+
+	var ttl time.Duration
+
+	if request.TTL != "" {
+		var err error
+
+		ttl, err = time.ParseDuration(request.TTL)
+		if err != nil || ttl <= 0 {
+			writeJSON(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("invalid ttl %q", request.TTL)})
+
+			return
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.setLevel(request.Prefix, level, ttl)
+
+	writeJSON(w, http.StatusOK, h.prefixLevel(request.Prefix, level))
+}
+
+func (h *LevelHandler) deleteLevel(w http.ResponseWriter, prefix string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.cancelRevert(prefix)
+	h.logger.ResetNameLevel(prefix)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setLevel sets the rule of the prefix, with [Logger.SetNameLevelFor] if ttl is set.
+// The restoration already scheduled for the prefix is canceled first,
+// so the rule restored is the one the prefix had before the first ttl.
+// The caller must hold the mutex.
+func (h *LevelHandler) setLevel(prefix string, level hclog.Level, ttl time.Duration) {
+	h.cancelRevert(prefix)
+
+	if ttl <= 0 {
+		h.logger.SetNameLevel(prefix, level)
+
+		return
+	}
+
+	h.reverts[prefix] = &levelRevert{
+		cancel:    h.logger.SetNameLevelFor(prefix, level, ttl),
+		expiresAt: time.Now().Add(ttl),
+	}
+}
+
+// cancelRevert restores the rule of the prefix, if its restoration is scheduled.
+// The caller must hold the mutex.
+func (h *LevelHandler) cancelRevert(prefix string) {
+	if pending, ok := h.reverts[prefix]; ok {
+		pending.cancel()
+		delete(h.reverts, prefix)
+	}
+}
+
+// prefixLevel describes the rule of the prefix. The caller must hold the mutex.
+func (h *LevelHandler) prefixLevel(prefix string, level hclog.Level) prefixLevel {
+	described := prefixLevel{Prefix: prefix, Level: level.String()}
+
+	if revert, ok := h.reverts[prefix]; ok && time.Now().Before(revert.expiresAt) {
+		described.ExpiresAt = &revert.expiresAt
+	}
+
+	return described
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
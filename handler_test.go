@@ -0,0 +1,243 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func serveLevels(t *testing.T, handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+
+	return recorder
+}
+
+func decodeResponse[T any](t *testing.T, recorder *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var response T
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expected response to be a valid JSON, got: %s", recorder.Body.String())
+	}
+
+	return response
+}
+
+func TestLevelHandlerList(t *testing.T) {
+	root := NewWithOptions(
+		zerolog.New(&bytes.Buffer{}),
+		WithLevel(hclog.Info),
+		WithNameLevel("raft.net", hclog.Error),
+		WithRegistry(NewRegistry()),
+	)
+	raft := root.Named("raft")
+	raftNet := raft.Named("net")
+	memberlist := root.Named("memberlist").With("peer", "node1")
+	handler := NewLevelHandler(root)
+
+	recorder := serveLevels(t, handler, http.MethodGet, "/", "")
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status to be %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected content type to be %q, got %q", "application/json", contentType)
+	}
+
+	response := decodeResponse[levelsResponse](t, recorder)
+
+	wantLoggers := []loggerLevel{{"memberlist", "info"}, {"raft", "info"}, {"raft.net", "error"}}
+	if !slices.Equal(response.Loggers, wantLoggers) {
+		t.Errorf("expected loggers to be %v, got %v", wantLoggers, response.Loggers)
+	}
+
+	wantLevels := []prefixLevel{{Prefix: "raft.net", Level: "error"}}
+	if !slices.Equal(response.Levels, wantLevels) {
+		t.Errorf("expected levels to be %v, got %v", wantLevels, response.Levels)
+	}
+
+	runtime.KeepAlive(raftNet)
+	runtime.KeepAlive(memberlist)
+}
+
+func TestLevelHandlerListRegistry(t *testing.T) {
+	registry := NewRegistry()
+	root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithRegistry(registry))
+	other := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithRegistry(registry), WithLevel(hclog.Error))
+	raft := root.Named("raft")
+	otherRaft := other.Named("raft")
+	serf := other.Named("serf")
+
+	response := decodeResponse[levelsResponse](t, serveLevels(t, NewLevelHandler(root), http.MethodGet, "/", ""))
+
+	wantLoggers := []loggerLevel{{"raft", "trace"}}
+	if !slices.Equal(response.Loggers, wantLoggers) {
+		t.Errorf("expected only the loggers of the family to be listed, got %v", response.Loggers)
+	}
+
+	unregistered := New(zerolog.New(&bytes.Buffer{}))
+	consul := unregistered.Named("consul")
+
+	response = decodeResponse[levelsResponse](t, serveLevels(t, NewLevelHandler(unregistered), http.MethodGet, "/", ""))
+	if len(response.Loggers) != 0 {
+		t.Errorf("expected no loggers to be listed without a registry, got %v", response.Loggers)
+	}
+
+	runtime.KeepAlive(raft)
+	runtime.KeepAlive(otherRaft)
+	runtime.KeepAlive(serf)
+	runtime.KeepAlive(consul)
+}
+
+func TestLevelHandlerForgetsCollectedLoggers(t *testing.T) {
+	root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithRegistry(NewRegistry()))
+	raft := root.Named("raft")
+
+	func() {
+		_ = root.Named("memberlist")
+	}()
+
+	runtime.GC()
+
+	response := decodeResponse[levelsResponse](t, serveLevels(t, NewLevelHandler(root), http.MethodGet, "/", ""))
+
+	wantLoggers := []loggerLevel{{"raft", "trace"}}
+	if !slices.Equal(response.Loggers, wantLoggers) {
+		t.Errorf("expected only raft logger to be listed, got %v", response.Loggers)
+	}
+
+	runtime.KeepAlive(raft)
+}
+
+func TestLevelHandlerPut(t *testing.T) {
+	root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevel(hclog.Info))
+	raft := root.Named("raft")
+	handler := NewLevelHandler(root)
+
+	recorder := serveLevels(t, handler, http.MethodPut, "/", `{"prefix": "raft", "level": "TRACE"}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status to be %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+
+	if response := decodeResponse[prefixLevel](t, recorder); response.Level != "trace" || response.ExpiresAt != nil {
+		t.Errorf("expected trace level without expiration, got %+v", response)
+	}
+
+	if raft.GetLevel() != hclog.Trace {
+		t.Errorf("expected level to be %v, got %v", hclog.Trace, raft.GetLevel())
+	}
+
+	recorder = serveLevels(t, handler, http.MethodGet, "/?prefix=raft", "")
+	if response := decodeResponse[prefixLevel](t, recorder); response.Level != "trace" {
+		t.Errorf("expected level of the prefix to be trace, got %+v", response)
+	}
+
+	recorder = serveLevels(t, handler, http.MethodDelete, "/?prefix=raft", "")
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("expected status to be %d, got %d", http.StatusNoContent, recorder.Code)
+	}
+
+	if raft.GetLevel() != hclog.Info {
+		t.Errorf("expected level to be %v, got %v", hclog.Info, raft.GetLevel())
+	}
+
+	recorder = serveLevels(t, handler, http.MethodGet, "/?prefix=raft", "")
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected status to be %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestLevelHandlerTTL(t *testing.T) {
+	root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevel(hclog.Info), WithNameLevel("raft", hclog.Warn))
+	raft := root.Named("raft")
+	handler := NewLevelHandler(root)
+
+	recorder := serveLevels(t, handler, http.MethodPut, "/", `{"prefix": "raft", "level": "debug", "ttl": "1h"}`)
+	if response := decodeResponse[prefixLevel](t, recorder); response.ExpiresAt == nil {
+		t.Errorf("expected expiration to be reported, got %+v", response)
+	}
+
+	serveLevels(t, handler, http.MethodPut, "/", `{"prefix": "raft", "level": "trace", "ttl": "20ms"}`)
+
+	if raft.GetLevel() != hclog.Trace {
+		t.Errorf("expected level to be %v, got %v", hclog.Trace, raft.GetLevel())
+	}
+
+	waitFor(t, func() bool { return raft.GetLevel() == hclog.Warn })
+
+	recorder = serveLevels(t, handler, http.MethodGet, "/?prefix=raft", "")
+	if response := decodeResponse[prefixLevel](t, recorder); response.Level != "warn" || response.ExpiresAt != nil {
+		t.Errorf("expected the level set before the first ttl to be restored, got %+v", response)
+	}
+
+	t.Run("without previous level", func(t *testing.T) {
+		serveLevels(t, handler, http.MethodPut, "/", `{"prefix": "memberlist", "level": "error", "ttl": "20ms"}`)
+
+		memberlist := root.Named("memberlist")
+		if memberlist.GetLevel() != hclog.Error {
+			t.Errorf("expected level to be %v, got %v", hclog.Error, memberlist.GetLevel())
+		}
+
+		waitFor(t, func() bool { return memberlist.GetLevel() == hclog.Info })
+	})
+
+	t.Run("canceled by put without ttl", func(t *testing.T) {
+		serveLevels(t, handler, http.MethodPut, "/", `{"prefix": "serf", "level": "error", "ttl": "20ms"}`)
+		serveLevels(t, handler, http.MethodPut, "/", `{"prefix": "serf", "level": "debug"}`)
+
+		time.Sleep(50 * time.Millisecond)
+
+		if level := root.Named("serf").GetLevel(); level != hclog.Debug {
+			t.Errorf("expected level to be %v, got %v", hclog.Debug, level)
+		}
+	})
+}
+
+func TestLevelHandlerErrors(t *testing.T) {
+	handler := NewLevelHandler(New(zerolog.New(&bytes.Buffer{})))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		err    string
+	}{
+		{"bad JSON", http.MethodPut, "/", `{"prefix":`, http.StatusBadRequest, "invalid request: unexpected EOF"},
+		{"unknown field", http.MethodPut, "/", `{"name": "raft"}`, http.StatusBadRequest, `invalid request: json: unknown field "name"`},
+		{"unknown level", http.MethodPut, "/", `{"prefix": "raft", "level": "loud"}`, http.StatusBadRequest, `unknown level "loud"`},
+		{"bad ttl", http.MethodPut, "/", `{"prefix": "raft", "level": "info", "ttl": "soon"}`, http.StatusBadRequest, `invalid ttl "soon"`},
+		{"negative ttl", http.MethodPut, "/", `{"prefix": "raft", "level": "info", "ttl": "-1m"}`, http.StatusBadRequest, `invalid ttl "-1m"`},
+		{"no prefix to delete", http.MethodDelete, "/", "", http.StatusBadRequest, "prefix query parameter is required"},
+		{"no level", http.MethodGet, "/?prefix=raft", "", http.StatusNotFound, `no level set for "raft"`},
+		{"method", http.MethodPost, "/", "", http.StatusMethodNotAllowed, "method not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serveLevels(t, handler, tt.method, tt.target, tt.body)
+
+			if recorder.Code != tt.status {
+				t.Errorf("expected status to be %d, got %d", tt.status, recorder.Code)
+			}
+
+			if response := decodeResponse[errorResponse](t, recorder); response.Error != tt.err {
+				t.Errorf("expected error to be %q, got %q", tt.err, response.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+// HCLogWriter is a [zerolog.LevelWriter] emitting the [zerolog] events into [hclog.Logger].
+// It's the reverse of [Logger]: it lets libraries accepting [zerolog.Logger] only
+// to write into [hclog.Logger].
+//
+// Every event is decoded and emitted with [hclog.Logger.Log]: the level is mapped back
+// with the [LevelMapping], the message becomes the message, the name field
+// makes a [hclog.Logger.Named] sublogger and the rest of the fields become the args.
+// The timestamp is dropped, since [hclog] adds its own.
+// Events which can't be decoded are emitted as is.
+// Events without a level, e.g. written with [zerolog.Logger.Log] or [HCLogWriter.Write],
+// are emitted at [hclog.Info] like [hclog.Logger.StandardWriter] does, since [hclog] drops
+// the [hclog.NoLevel] ones unless the logger is set to it.
+type HCLogWriter struct {
+	logger hclog.Logger
+	config *config
+
+	mu    sync.RWMutex
+	named map[string]hclog.Logger
+}
+
+// NewHCLogWriter creates an instance of [HCLogWriter] emitting into provided [hclog.Logger].
+// Only [WithNameField] and [WithLevelMapping] options are taken into account.
+func NewHCLogWriter(logger hclog.Logger, opts ...Option) *HCLogWriter {
+	return &HCLogWriter{
+		logger: logger,
+		config: newConfig(opts),
+		named:  make(map[string]hclog.Logger),
+	}
+}
+
+// NewZerolog creates a [zerolog.Logger] emitting into provided [hclog.Logger],
+// see [HCLogWriter].
+//
+//	pluginLogger := hclog.New(&hclog.LoggerOptions{JSONFormat: true})
+//	client := somelib.New(hclogzerolog.NewZerolog(pluginLogger.Named("somelib")))
+func NewZerolog(logger hclog.Logger, opts ...Option) zerolog.Logger {
+	return zerolog.New(NewHCLogWriter(logger, opts...))
+}
+
+// Write implements [io.Writer], the level is taken from the event.
+func (w *HCLogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w *HCLogWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	event, ok := decodeEvent(p)
+	if !ok {
+		w.logger.Log(w.hclogLevel(level), string(bytes.TrimSpace(p)))
+
+		return len(p), nil
+	}
+
+	logger := w.logger
+	name := ""
+	msg := ""
+	args := make([]any, 0, len(event))
+
+	for _, field := range event {
+		switch field.key {
+		case zerolog.LevelFieldName:
+			if level == zerolog.NoLevel {
+				if s, ok := field.value.(string); ok {
+					if parsed, err := zerolog.ParseLevel(s); err == nil {
+						level = parsed
+					}
+				}
+			}
+		case zerolog.MessageFieldName:
+			msg = fmt.Sprint(field.value)
+		case zerolog.TimestampFieldName:
+			// hclog adds its own timestamp
+		case w.config.nameField:
+			name = fmt.Sprint(field.value)
+		default:
+			args = append(args, field.key, field.value)
+		}
+	}
+
+	if name != "" {
+		logger = w.namedLogger(name)
+	}
+
+	logger.Log(w.hclogLevel(level), msg, args...)
+
+	return len(p), nil
+}
+
+// hclogLevel maps the level of the event back, the unknown levels and no level become [hclog.Info].
+func (w *HCLogWriter) hclogLevel(level zerolog.Level) hclog.Level {
+	mapped, ok := w.config.levelMapping.toHCLog(level)
+	if !ok || mapped == hclog.NoLevel {
+		return hclog.Info
+	}
+
+	return mapped
+}
+
+// namedLogger returns the sublogger of the given name, they are cached
+// since [zerolog] loggers tend to use the same names over and over.
+func (w *HCLogWriter) namedLogger(name string) hclog.Logger {
+	w.mu.RLock()
+	logger, ok := w.named[name]
+	w.mu.RUnlock()
+
+	if ok {
+		return logger
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if logger, ok = w.named[name]; !ok {
+		logger = w.logger.Named(name)
+		w.named[name] = logger
+	}
+
+	return logger
+}
+
+type eventField struct {
+	key   string
+	value any
+}
+
+// decodeEvent decodes the JSON object keeping the order of the fields.
+func decodeEvent(p []byte) ([]eventField, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+
+	var event []eventField
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+
+		key, _ := token.(string)
+
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false
+		}
+
+		event = append(event, eventField{key, value})
+	}
+
+	return event, true
+}
@@ -0,0 +1,155 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestHCLogWriter(t *testing.T) {
+	var _ zerolog.LevelWriter = NewHCLogWriter(hclog.NewNullLogger())
+
+	// the timestamp is disabled, so the messages can be compared
+	newHCLog := func(buf *bytes.Buffer) hclog.Logger {
+		return hclog.New(&hclog.LoggerOptions{Output: buf, Level: hclog.Trace, JSONFormat: true, DisableTime: true})
+	}
+
+	t.Run("emits zerolog events into hclog", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := NewZerolog(newHCLog(buf))
+
+		logger.Warn().Str(DefaultNameField, "raft").Int("term", 2).Str(customFieldName, customFieldValue).Msg(messageToLog)
+
+		want := map[string]any{
+			"@level":        "warn",
+			"@message":      messageToLog,
+			"@module":       "raft",
+			"term":          float64(2),
+			customFieldName: customFieldValue,
+		}
+
+		if msg := decodeLine(t, buf); !reflect.DeepEqual(msg, want) {
+			t.Errorf("expected hclog message to be\n %v\n got\n %v", want, msg)
+		}
+	})
+
+	t.Run("maps levels", func(t *testing.T) {
+		tests := []struct {
+			level zerolog.Level
+			want  string
+		}{
+			{zerolog.TraceLevel, "trace"},
+			{zerolog.DebugLevel, "debug"},
+			{zerolog.InfoLevel, "info"},
+			{zerolog.WarnLevel, "warn"},
+			{zerolog.ErrorLevel, "error"},
+			{zerolog.FatalLevel, "error"},
+			{zerolog.PanicLevel, "error"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.level.String(), func(t *testing.T) {
+				buf := &bytes.Buffer{}
+				writer := NewHCLogWriter(newHCLog(buf))
+
+				if _, err := writer.WriteLevel(tt.level, []byte(`{"message":"test message"}`)); err != nil {
+					t.Fatalf("expected no error while writing, got: %v", err)
+				}
+
+				if msg := decodeLine(t, buf); msg["@level"] != tt.want {
+					t.Errorf("expected level to be %q, got %v", tt.want, msg["@level"])
+				}
+			})
+		}
+	})
+
+	t.Run("takes the level from the event when written as io.Writer", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		writer := NewHCLogWriter(newHCLog(buf))
+
+		if _, err := writer.Write([]byte(`{"level":"debug","message":"test message"}`)); err != nil {
+			t.Fatalf("expected no error while writing, got: %v", err)
+		}
+
+		if msg := decodeLine(t, buf); msg["@level"] != "debug" {
+			t.Errorf("expected level to be %q, got %v", "debug", msg["@level"])
+		}
+	})
+
+	t.Run("custom name field", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := NewZerolog(newHCLog(buf).Named("plugin"), WithNameField("subsystem"))
+
+		logger.Info().Str("subsystem", "db").Msg(messageToLog)
+
+		if msg := decodeLine(t, buf); msg["@module"] != "plugin.db" {
+			t.Errorf("expected module to be %q, got %v", "plugin.db", msg["@module"])
+		}
+	})
+
+	t.Run("emits undecodable events as is", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		writer := NewHCLogWriter(newHCLog(buf))
+
+		if _, err := writer.WriteLevel(zerolog.ErrorLevel, []byte("not a json\n")); err != nil {
+			t.Fatalf("expected no error while writing, got: %v", err)
+		}
+
+		msg := decodeLine(t, buf)
+		if msg["@level"] != "error" || msg["@message"] != "not a json" {
+			t.Errorf("expected raw message at error level, got %v", msg)
+		}
+	})
+
+	t.Run("emits events without a level at info", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogger := hclog.New(&hclog.LoggerOptions{Output: buf, Level: hclog.Info, JSONFormat: true})
+		writer := NewHCLogWriter(hclogger)
+
+		zl := zerolog.New(writer)
+		zl.Log().Msg(messageToLog)
+
+		if _, err := writer.Write([]byte("not a json\n")); err != nil {
+			t.Fatalf("expected no error while writing, got: %v", err)
+		}
+
+		New(zerolog.New(writer)).Log(hclog.NoLevel, customFieldValue)
+
+		var msgs []string
+
+		for _, msg := range decodeLines(t, buf) {
+			if msg["@level"] != "info" {
+				t.Errorf("expected message at info level, got %v", msg)
+			}
+
+			msgs = append(msgs, fmt.Sprint(msg["@message"]))
+		}
+
+		if want := []string{messageToLog, "not a json", customFieldValue}; !slices.Equal(msgs, want) {
+			t.Errorf("expected messages to be %v, got %v", want, msgs)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		viaZerolog := &bytes.Buffer{}
+		direct := &bytes.Buffer{}
+
+		loggers := []hclog.Logger{
+			New(NewZerolog(newHCLog(viaZerolog)).Level(zerolog.TraceLevel)),
+			newHCLog(direct),
+		}
+
+		for _, logger := range loggers {
+			logger.Named("raft").Named("net").With("peer", "node1").Warn(messageToLog, "term", 2, customFieldName, customFieldValue)
+		}
+
+		if got, want := decodeLine(t, viaZerolog), decodeLine(t, direct); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected message emitted through zerolog to be\n %v\n got\n %v", want, got)
+		}
+	})
+}
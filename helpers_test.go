@@ -0,0 +1,89 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a [bytes.Buffer] safe for concurrent use, so that the messages written
+// in the background, e.g. by the signal handler or the timers, can be read by the test.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func (b *syncBuffer) lines() []string {
+	return splitLines(b.String())
+}
+
+// splitLines splits the output into the lines, there are none if it's empty.
+func splitLines(output string) []string {
+	if output == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSpace(output), "\n")
+}
+
+// decodeLines decodes every line written into the buffer as a JSON message.
+func decodeLines(t *testing.T, buf fmt.Stringer) []map[string]any {
+	t.Helper()
+
+	lines := splitLines(buf.String())
+	msgs := make([]map[string]any, 0, len(lines))
+
+	for _, line := range lines {
+		var msg map[string]any
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", line)
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs
+}
+
+// decodeLine decodes the single JSON message written into the buffer.
+func decodeLine(t *testing.T, buf fmt.Stringer) map[string]any {
+	t.Helper()
+
+	msgs := decodeLines(t, buf)
+	if len(msgs) != 1 {
+		t.Fatalf("Expected log output to be a single JSON line, got: %s", buf)
+	}
+
+	return msgs[0]
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition is not met in time")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,117 @@
+package hclogzerolog
+
+import (
+	"io"
+	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+// InterceptLogger is a [Logger] implementing [hclog.InterceptLogger].
+//
+// Every message is written to the wrapped [zerolog.Logger] as usual and is also
+// delivered to the registered [hclog.SinkAdapter]s. Sinks receive the messages
+// regardless of the level of the logger, they are expected to filter them by their own level.
+// Sinks are shared by the whole family of loggers derived from the one they were registered with.
+type InterceptLogger struct {
+	*Logger
+}
+
+// NewInterceptLogger creates an instance of [InterceptLogger] wrapping provided [zerolog.Logger]
+// configured with the given options.
+//
+//	logger := hclogzerolog.NewInterceptLogger(log.Logger)
+//	logger.RegisterSink(hclog.NewSinkAdapter(&hclog.LoggerOptions{
+//		Output: consoleStream,
+//		Level:  hclog.Debug,
+//	}))
+func NewInterceptLogger(logger zerolog.Logger, opts ...Option) *InterceptLogger {
+	opts = append(slices.Clip(opts), func(c *config) {
+		c.sinks = &sinkSet{sinks: make(map[hclog.SinkAdapter]struct{})}
+	})
+
+	return &InterceptLogger{NewWithOptions(logger, opts...)}
+}
+
+func (i *InterceptLogger) With(args ...any) hclog.Logger {
+	return &InterceptLogger{i.Logger.with(args)}
+}
+
+func (i *InterceptLogger) Named(name string) hclog.Logger {
+	return i.NamedIntercept(name)
+}
+
+func (i *InterceptLogger) ResetNamed(name string) hclog.Logger {
+	return i.ResetNamedIntercept(name)
+}
+
+func (i *InterceptLogger) NamedIntercept(name string) hclog.InterceptLogger {
+	return &InterceptLogger{i.Logger.named(name)}
+}
+
+func (i *InterceptLogger) ResetNamedIntercept(name string) hclog.InterceptLogger {
+	return &InterceptLogger{i.Logger.derive(name, i.implied)}
+}
+
+// RegisterSink attaches the sink to the whole family of loggers.
+func (i *InterceptLogger) RegisterSink(sink hclog.SinkAdapter) {
+	i.config.sinks.register(sink)
+}
+
+// DeregisterSink detaches the sink from the whole family of loggers.
+func (i *InterceptLogger) DeregisterSink(sink hclog.SinkAdapter) {
+	i.config.sinks.deregister(sink)
+}
+
+func (i *InterceptLogger) StandardLoggerIntercept(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return i.StandardLogger(opts)
+}
+
+func (i *InterceptLogger) StandardWriterIntercept(opts *hclog.StandardLoggerOptions) io.Writer {
+	return i.StandardWriter(opts)
+}
+
+// sinkSet is a set of sinks shared by the family of [InterceptLogger]s.
+type sinkSet struct {
+	mu    sync.Mutex
+	count atomic.Int32
+	sinks map[hclog.SinkAdapter]struct{}
+}
+
+func (s *sinkSet) register(sink hclog.SinkAdapter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sinks[sink] = struct{}{}
+	s.count.Store(int32(len(s.sinks)))
+}
+
+func (s *sinkSet) deregister(sink hclog.SinkAdapter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sinks, sink)
+	s.count.Store(int32(len(s.sinks)))
+}
+
+// accept delivers the message to every sink, one message at a time, like [hclog] does.
+func (s *sinkSet) accept(name string, level hclog.Level, msg string, implied, args []any) {
+	if s.count.Load() == 0 {
+		return
+	}
+
+	all := make([]any, 0, len(implied)+len(args))
+	all = append(all, implied...)
+	all = append(all, args...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sink := range s.sinks {
+		sink.Accept(name, level, msg, all...)
+	}
+}
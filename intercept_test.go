@@ -0,0 +1,186 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+type acceptedMessage struct {
+	name  string
+	level hclog.Level
+	msg   string
+	args  []any
+}
+
+type recordingSink struct {
+	messages []acceptedMessage
+}
+
+func (s *recordingSink) Accept(name string, level hclog.Level, msg string, args ...any) {
+	s.messages = append(s.messages, acceptedMessage{name, level, msg, args})
+}
+
+func TestInterceptLogger(t *testing.T) {
+	var _ hclog.InterceptLogger = NewInterceptLogger(zerolog.Nop())
+
+	t.Run("delivers messages to zerolog and sinks", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		sink := &recordingSink{}
+		hclogLogger := NewInterceptLogger(zerolog.New(buf).Level(zerolog.InfoLevel))
+
+		hclogLogger.RegisterSink(sink)
+
+		logger := hclogLogger.NamedIntercept("raft").With("peer", "node1")
+		logger.Info(messageToLog, customFieldName, customFieldValue)
+		logger.Debug(messageToLog)
+
+		msg := &message{}
+		if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+		}
+
+		if msg.Message != messageToLog || msg.HCLogName != "raft" || msg.CustomField != customFieldValue {
+			t.Errorf("expected message to be written to zerolog, got %+v", msg)
+		}
+
+		want := []acceptedMessage{
+			{"raft", hclog.Info, messageToLog, []any{"peer", "node1", customFieldName, customFieldValue}},
+			{"raft", hclog.Debug, messageToLog, []any{"peer", "node1"}},
+		}
+
+		if !reflect.DeepEqual(sink.messages, want) {
+			t.Errorf("expected sink to accept\n %+v\n got\n %+v", want, sink.messages)
+		}
+	})
+
+	t.Run("derived loggers are intercepting", func(t *testing.T) {
+		hclogLogger := NewInterceptLogger(zerolog.Nop())
+
+		derived := map[string]hclog.Logger{
+			"With":                hclogLogger.With("peer", "node1"),
+			"Named":               hclogLogger.Named("raft"),
+			"ResetNamed":          hclogLogger.ResetNamed("raft"),
+			"NamedIntercept":      hclogLogger.NamedIntercept("raft"),
+			"ResetNamedIntercept": hclogLogger.ResetNamedIntercept("raft"),
+		}
+
+		for name, logger := range derived {
+			if _, ok := logger.(hclog.InterceptLogger); !ok {
+				t.Errorf("expected %s to return hclog.InterceptLogger, got %T", name, logger)
+			}
+		}
+	})
+
+	t.Run("sinks are shared by the family", func(t *testing.T) {
+		sink := &recordingSink{}
+		hclogLogger := NewInterceptLogger(zerolog.Nop())
+		named := hclogLogger.NamedIntercept("raft")
+
+		named.RegisterSink(sink)
+		hclogLogger.ResetNamed("memberlist").Warn(messageToLog)
+
+		named.DeregisterSink(sink)
+		hclogLogger.Warn(messageToLog)
+
+		want := []acceptedMessage{{"memberlist", hclog.Warn, messageToLog, []any{}}}
+		if !reflect.DeepEqual(sink.messages, want) {
+			t.Errorf("expected sink to accept\n %+v\n got\n %+v", want, sink.messages)
+		}
+	})
+
+	t.Run("hclog sink adapter", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		hclogLogger := NewInterceptLogger(zerolog.Nop())
+
+		hclogLogger.RegisterSink(hclog.NewSinkAdapter(&hclog.LoggerOptions{
+			Output:     output,
+			Level:      hclog.Warn,
+			JSONFormat: true,
+		}))
+
+		hclogLogger.Named("raft").Info(messageToLog)
+
+		if output.Len() != 0 {
+			t.Errorf("expected sink to drop the message below its level, got: %s", output.String())
+		}
+
+		hclogLogger.Named("raft").Error(messageToLog, customFieldName, customFieldValue)
+
+		var msg map[string]any
+		if err := json.Unmarshal(output.Bytes(), &msg); err != nil {
+			t.Fatalf("Expected sink output to be a valid JSON, got: %s", output.String())
+		}
+
+		if msg["@module"] != "raft" || msg["@message"] != messageToLog || msg[customFieldName] != customFieldValue {
+			t.Errorf("expected sink to write the message, got %v", msg)
+		}
+	})
+
+	t.Run("standard writer", func(t *testing.T) {
+		sink := &recordingSink{}
+		hclogLogger := NewInterceptLogger(zerolog.Nop())
+		hclogLogger.RegisterSink(sink)
+
+		hclogLogger.StandardLoggerIntercept(nil).Println(messageToLog)
+
+		if len(sink.messages) != 1 || sink.messages[0].msg != messageToLog {
+			t.Errorf("expected sink to accept the standard logger message, got %+v", sink.messages)
+		}
+	})
+
+	t.Run("location", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogLogger := NewInterceptLogger(zerolog.New(buf), WithLocation())
+
+		_, file, line, _ := runtime.Caller(0)
+		hclogLogger.Info(messageToLog)
+
+		var msg map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+		}
+
+		assertCaller(t, msg, file, line+1)
+	})
+}
+
+func TestInterceptLoggerRace(t *testing.T) {
+	hclogLogger := NewInterceptLogger(zerolog.New(io.Discard))
+
+	var wg sync.WaitGroup
+
+	for worker := range 4 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			sink := hclog.NewSinkAdapter(&hclog.LoggerOptions{Output: io.Discard})
+
+			for range 100 {
+				hclogLogger.RegisterSink(sink)
+				hclogLogger.DeregisterSink(sink)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			logger := hclogLogger.Named("raft").With("worker", worker)
+
+			for i := range 1000 {
+				logger.Info(messageToLog, "i", i)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
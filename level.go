@@ -0,0 +1,145 @@
+package hclogzerolog
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+// LevelMapping maps [hclog] levels to [zerolog] ones.
+// It's used to write the messages as well as to set and report the level of the [Logger].
+type LevelMapping map[hclog.Level]zerolog.Level
+
+// DefaultLevelMapping returns the mapping of the [hclog] levels to the [zerolog] levels of the same name.
+// [hclog.NoLevel] is mapped to [zerolog.NoLevel] and [hclog.Off] to [zerolog.Disabled].
+func DefaultLevelMapping() LevelMapping {
+	return LevelMapping{
+		hclog.Trace:   zerolog.TraceLevel,
+		hclog.Debug:   zerolog.DebugLevel,
+		hclog.Info:    zerolog.InfoLevel,
+		hclog.Warn:    zerolog.WarnLevel,
+		hclog.Error:   zerolog.ErrorLevel,
+		hclog.NoLevel: zerolog.NoLevel,
+		hclog.Off:     zerolog.Disabled,
+	}
+}
+
+// hclogLevels lists [hclog] levels in the order they are looked up by [LevelMapping.toHCLog].
+var hclogLevels = []hclog.Level{hclog.Trace, hclog.Debug, hclog.Info, hclog.Warn, hclog.Error, hclog.NoLevel, hclog.Off}
+
+func (m LevelMapping) toZerolog(level hclog.Level) (zerolog.Level, bool) {
+	mapped, ok := m[level]
+
+	return mapped, ok
+}
+
+// toHCLog maps the [zerolog] threshold back to the [hclog] level.
+// The least severe [hclog] level mapped exactly to the threshold wins.
+// Otherwise, it's the least severe of [hclog.Trace] - [hclog.Error] mapped above the threshold,
+// or [hclog.Error] if none is, e.g. [zerolog.FatalLevel] is reported as [hclog.Error].
+// Thresholds out of [zerolog.TraceLevel] - [zerolog.Disabled] range are unknown.
+func (m LevelMapping) toHCLog(level zerolog.Level) (hclog.Level, bool) {
+	for _, hclogLevel := range hclogLevels {
+		if mapped, ok := m[hclogLevel]; ok && mapped == level {
+			return hclogLevel, true
+		}
+	}
+
+	if level < zerolog.TraceLevel || level > zerolog.Disabled {
+		return hclog.NoLevel, false
+	}
+
+	for i := hclog.Trace; i <= hclog.Error; i++ {
+		if mapped, ok := m[i]; ok && mapped > level {
+			return i, true
+		}
+	}
+
+	return hclog.Error, true
+}
+
+// LevelMode defines how the level is shared between a [Logger]
+// and the loggers derived from it with With, Named and ResetNamed.
+type LevelMode int
+
+const (
+	// SharedLevels makes the whole family of loggers share a single level,
+	// so [Logger.SetLevel] called on any of them affects all the others.
+	// This is the default, same as for [hclog].
+	SharedLevels LevelMode = iota
+	// IndependentLevels makes every derived logger start with a copy of the parent's level.
+	// [Logger.SetLevel] affects only the logger it's called on.
+	// Same as [hclog.LoggerOptions] IndependentLevels.
+	IndependentLevels
+	// SyncParentLevel makes [Logger.SetLevel] affect the logger it's called on and
+	// all the loggers derived from it, but neither the parent nor the siblings.
+	// The level set most recently along the chain of parents wins.
+	// Same as [hclog.LoggerOptions] SyncParentLevel.
+	SyncParentLevel
+)
+
+// permissiveLevel is the level of the zerolog loggers built by the wrapper.
+// The wrapper does level filtering on its own using levelHolder.
+const permissiveLevel = zerolog.Level(math.MinInt8)
+
+// levelHolder stores the level of a logger and is safe for concurrent use.
+type levelHolder struct {
+	mode  LevelMode
+	level atomic.Int32
+	// epoch is the clock value at the time the level was set, [SyncParentLevel] mode only
+	epoch atomic.Uint64
+	// parent is the holder of the logger this one was derived from, [SyncParentLevel] mode only
+	parent *levelHolder
+	// clock is shared among the family, [SyncParentLevel] mode only
+	clock *atomic.Uint64
+	// elevations are the temporary levels, see [Logger.SetLevelFor]
+	elevations elevationStack[zerolog.Level]
+}
+
+func newLevelHolder(level zerolog.Level, mode LevelMode) *levelHolder {
+	holder := &levelHolder{mode: mode, clock: &atomic.Uint64{}}
+	holder.set(level)
+
+	return holder
+}
+
+// get returns the level, resolving it along the parents in [SyncParentLevel] mode.
+func (h *levelHolder) get() zerolog.Level {
+	if h.mode != SyncParentLevel {
+		return zerolog.Level(h.level.Load())
+	}
+
+	latest, latestEpoch := h, h.epoch.Load()
+
+	for p := h.parent; p != nil; p = p.parent {
+		if epoch := p.epoch.Load(); epoch > latestEpoch {
+			latest, latestEpoch = p, epoch
+		}
+	}
+
+	return zerolog.Level(latest.level.Load())
+}
+
+func (h *levelHolder) set(level zerolog.Level) {
+	h.level.Store(int32(level))
+
+	if h.mode == SyncParentLevel {
+		h.epoch.Store(h.clock.Add(1))
+	}
+}
+
+// derive returns the holder for a logger derived from the one owning h.
+func (h *levelHolder) derive() *levelHolder {
+	switch h.mode {
+	case IndependentLevels:
+		return newLevelHolder(h.get(), h.mode)
+	case SyncParentLevel:
+		return &levelHolder{mode: h.mode, parent: h, clock: h.clock}
+	case SharedLevels:
+		return h
+	default:
+		return h
+	}
+}
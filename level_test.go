@@ -0,0 +1,186 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestSharedLevels(t *testing.T) {
+	root := New(zerolog.New(&bytes.Buffer{}).Level(zerolog.InfoLevel))
+	named := root.Named("raft")
+	with := named.With("peer", "node1")
+	reset := with.ResetNamed("memberlist")
+
+	family := map[string]hclog.Logger{"root": root, "named": named, "with": with, "reset": reset}
+
+	for name, logger := range family {
+		logger.SetLevel(hclog.Debug)
+
+		for otherName, other := range family {
+			if other.GetLevel() != hclog.Debug {
+				t.Errorf("expected %s level to follow SetLevel on %s, got %v", otherName, name, other.GetLevel())
+			}
+		}
+
+		logger.SetLevel(hclog.Info)
+	}
+}
+
+func TestIndependentLevels(t *testing.T) {
+	root := NewWithLevelMode(zerolog.New(&bytes.Buffer{}).Level(zerolog.InfoLevel), IndependentLevels)
+	root.SetLevel(hclog.Warn)
+
+	named := root.Named("raft")
+	sibling := root.With("peer", "node1")
+
+	if named.GetLevel() != hclog.Warn {
+		t.Errorf("expected derived logger to start with the parent level %v, got %v", hclog.Warn, named.GetLevel())
+	}
+
+	named.SetLevel(hclog.Debug)
+	root.SetLevel(hclog.Error)
+
+	wants := map[string]struct {
+		logger hclog.Logger
+		level  hclog.Level
+	}{
+		"root":    {root, hclog.Error},
+		"named":   {named, hclog.Debug},
+		"sibling": {sibling, hclog.Warn},
+	}
+
+	for name, want := range wants {
+		if want.logger.GetLevel() != want.level {
+			t.Errorf("expected %s level to be %v, got %v", name, want.level, want.logger.GetLevel())
+		}
+	}
+}
+
+func TestSyncParentLevel(t *testing.T) {
+	root := NewWithLevelMode(zerolog.New(&bytes.Buffer{}).Level(zerolog.InfoLevel), SyncParentLevel)
+
+	a := root.Named("a")
+	a.SetLevel(hclog.Error)
+
+	b := a.Named("b")
+	c := a.Named("c")
+	d := b.With("peer", "node1")
+
+	type step struct {
+		set   hclog.Logger
+		level hclog.Level
+		wants map[string]hclog.Level
+	}
+
+	loggers := map[string]hclog.Logger{"root": root, "a": a, "b": b, "c": c, "d": d}
+
+	steps := []step{
+		{nil, hclog.NoLevel, map[string]hclog.Level{
+			"root": hclog.Info, "a": hclog.Error, "b": hclog.Error, "c": hclog.Error, "d": hclog.Error,
+		}},
+		{b, hclog.Info, map[string]hclog.Level{
+			"root": hclog.Info, "a": hclog.Error, "b": hclog.Info, "c": hclog.Error, "d": hclog.Info,
+		}},
+		{a, hclog.Warn, map[string]hclog.Level{
+			"root": hclog.Info, "a": hclog.Warn, "b": hclog.Warn, "c": hclog.Warn, "d": hclog.Warn,
+		}},
+		{root, hclog.Trace, map[string]hclog.Level{
+			"root": hclog.Trace, "a": hclog.Trace, "b": hclog.Trace, "c": hclog.Trace, "d": hclog.Trace,
+		}},
+		{d, hclog.Debug, map[string]hclog.Level{
+			"root": hclog.Trace, "a": hclog.Trace, "b": hclog.Trace, "c": hclog.Trace, "d": hclog.Debug,
+		}},
+	}
+
+	for i, step := range steps {
+		if step.set != nil {
+			step.set.SetLevel(step.level)
+		}
+
+		for name, want := range step.wants {
+			if got := loggers[name].GetLevel(); got != want {
+				t.Errorf("step %d: expected %s level to be %v, got %v", i, name, want, got)
+			}
+		}
+	}
+}
+
+func TestLevelAppliesToEmittedEvents(t *testing.T) {
+	buf := &bytes.Buffer{}
+	root := New(zerolog.New(buf).Level(zerolog.InfoLevel))
+	named := root.Named("raft")
+	writer := named.StandardWriter(nil)
+
+	named.Debug(messageToLog)
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected debug message to be dropped, got: %s", buf.String())
+	}
+
+	root.SetLevel(hclog.Debug)
+	named.Debug(messageToLog)
+
+	if buf.Len() == 0 {
+		t.Fatalf("expected debug message to be emitted after SetLevel on the parent")
+	}
+
+	buf.Reset()
+	root.SetLevel(hclog.Off)
+
+	named.Error(messageToLog)
+
+	if _, err := writer.Write([]byte("standard writer message\n")); err != nil {
+		t.Fatalf("expected no error while writing, got: %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be emitted when the level is Off, got: %s", buf.String())
+	}
+}
+
+func TestLevelRace(t *testing.T) {
+	modes := map[string]LevelMode{
+		"SharedLevels":      SharedLevels,
+		"IndependentLevels": IndependentLevels,
+		"SyncParentLevel":   SyncParentLevel,
+	}
+
+	levels := []hclog.Level{hclog.Trace, hclog.Debug, hclog.Info, hclog.Warn, hclog.Error, hclog.Off}
+
+	for name, mode := range modes {
+		t.Run(name, func(t *testing.T) {
+			root := NewWithLevelMode(zerolog.New(io.Discard), mode)
+
+			var wg sync.WaitGroup
+
+			for worker := range 8 {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					logger := root.Named("raft").With("worker", worker)
+
+					for i := range 1000 {
+						logger.Info(messageToLog, "i", i)
+						logger.Debug(messageToLog, "i", i)
+						_ = logger.IsDebug()
+						_ = logger.Named("net").GetLevel()
+
+						if i%10 == worker {
+							logger.SetLevel(levels[i%len(levels)])
+							root.SetLevel(levels[(i+worker)%len(levels)])
+						}
+					}
+				}()
+			}
+
+			wg.Wait()
+		})
+	}
+}
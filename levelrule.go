@@ -0,0 +1,112 @@
+package hclogzerolog
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// LevelRule rewrites the level of the matching messages before they are written, see [WithLevelRules].
+// A message matches if all the conditions set in the rule hold, a rule with no conditions matches every message.
+//
+//	// raft reports the peers down during rolling restarts as errors
+//	failedToContact := &hclogzerolog.LevelRule{
+//		Name:          "raft",
+//		Levels:        []hclog.Level{hclog.Error},
+//		MessagePrefix: "failed to contact",
+//		Level:         hclog.Warn,
+//	}
+type LevelRule struct {
+	// Name is the prefix of the names of the loggers the rule applies to, like in [WithNameLevel].
+	// Empty name matches all the loggers.
+	Name string
+	// Levels are the levels of the messages the rule applies to, all of them if empty.
+	Levels []hclog.Level
+	// Message matches the messages equal to it, if not empty.
+	Message string
+	// MessagePrefix matches the messages starting with it, if not empty.
+	MessagePrefix string
+	// MessageRegexp matches the messages it matches, if set.
+	MessageRegexp *regexp.Regexp
+	// Args matches the messages it returns true for the args of, if set.
+	// The args are the ones given to the logging method, without the implied ones.
+	Args func(args []any) bool
+	// Level is the level the matching messages are written at. [hclog.Off] drops them.
+	// It must be set, the rules left at [hclog.NoLevel] are ignored rather than letting the messages through.
+	Level hclog.Level
+
+	fired atomic.Uint64
+}
+
+// WithLevelRules rewrites the levels of the messages with the rules, the first matching rule wins.
+// The rules are applied before the level of the message is checked, so a message may be
+// written at a level it would be dropped at, or vice versa.
+// Options given several times add the rules to the ones given before.
+// Rules with the levels unknown to the [LevelMapping] or without the level are ignored.
+//
+// The rules are the pointers, so the number of messages rewritten by a rule can be checked
+// with [LevelRule.Fired] at any time.
+func WithLevelRules(rules ...*LevelRule) Option {
+	return func(c *config) {
+		c.levelRules = append(c.levelRules, rules...)
+	}
+}
+
+// Fired returns the number of messages the rule has rewritten the level of.
+func (r *LevelRule) Fired() uint64 {
+	return r.fired.Load()
+}
+
+// matches reports whether the message matches the conditions of the rule except the name.
+func (r *LevelRule) matches(level hclog.Level, msg string, args []any) bool {
+	if len(r.Levels) > 0 && !slices.Contains(r.Levels, level) {
+		return false
+	}
+
+	if r.Message != "" && msg != r.Message {
+		return false
+	}
+
+	if r.MessagePrefix != "" && !strings.HasPrefix(msg, r.MessagePrefix) {
+		return false
+	}
+
+	if r.MessageRegexp != nil && !r.MessageRegexp.MatchString(msg) {
+		return false
+	}
+
+	return r.Args == nil || r.Args(args)
+}
+
+// levelRulesFor returns the rules applying to the logger with the given name and mapping.
+func (c *config) levelRulesFor(name string, mapping LevelMapping) []*LevelRule {
+	var rules []*LevelRule
+
+	for _, rule := range c.levelRules {
+		if _, ok := mapping.toZerolog(rule.Level); !ok || rule.Level == hclog.NoLevel {
+			continue
+		}
+
+		if rule.Name == "" || hasNamePrefix(name, rule.Name, c.nameSeparator) {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// applyLevelRules returns the level of the message rewritten by the first matching rule, if any.
+func (l *Logger) applyLevelRules(level hclog.Level, msg string, args []any) hclog.Level {
+	for _, rule := range l.levelRules {
+		if rule.matches(level, msg, args) {
+			rule.fired.Add(1)
+
+			return rule.Level
+		}
+	}
+
+	return level
+}
@@ -0,0 +1,147 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestLevelRules(t *testing.T) {
+	failedToContact := &LevelRule{
+		Name:          "raft",
+		Levels:        []hclog.Level{hclog.Error},
+		MessagePrefix: "failed to contact",
+		Level:         hclog.Warn,
+	}
+	suspect := &LevelRule{
+		Name:          "memberlist",
+		MessageRegexp: regexp.MustCompile(`^Suspect \S+ has failed`),
+		Level:         hclog.Info,
+	}
+	heartbeat := &LevelRule{
+		Message: "heartbeat",
+		Args: func(args []any) bool {
+			return hasKey(args, "peer")
+		},
+		Level: hclog.Off,
+	}
+	unknown := &LevelRule{Message: "heartbeat", Level: hclog.Level(999)}
+
+	buf := &bytes.Buffer{}
+	root := NewWithOptions(
+		zerolog.New(buf),
+		WithLevel(hclog.Info),
+		WithLevelField("hclog_level"),
+		WithLevelRules(failedToContact, suspect),
+		WithLevelRules(unknown, heartbeat),
+	)
+
+	tests := []struct {
+		name      string
+		level     hclog.Level
+		msg       string
+		args      []any
+		wantLevel string
+	}{
+		{"raft", hclog.Error, "failed to contact node2", nil, "warn"},
+		{"raft.net", hclog.Error, "failed to contact node2", nil, "warn"},
+		{"raft", hclog.Warn, "failed to contact node2", nil, "warn"},
+		{"raft", hclog.Error, "failed to apply", nil, "error"},
+		{"rafting", hclog.Error, "failed to contact node2", nil, "error"},
+		{"memberlist", hclog.Warn, "Suspect node2 has failed, no acks received", nil, "info"},
+		{"memberlist", hclog.Warn, "Marking node2 as failed", nil, "warn"},
+		{"raft", hclog.Info, "heartbeat", []any{"peer", "node2"}, ""},
+		{"raft", hclog.Info, "heartbeat", nil, "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.msg, func(t *testing.T) {
+			buf.Reset()
+
+			root.ResetNamed(tt.name).Log(tt.level, tt.msg, tt.args...)
+
+			if tt.wantLevel == "" {
+				if buf.Len() != 0 {
+					t.Errorf("expected message to be dropped, got: %s", buf.String())
+				}
+
+				return
+			}
+
+			msg := decodeLine(t, buf)
+
+			if msg["level"] != tt.wantLevel {
+				t.Errorf("expected level to be %q, got %v", tt.wantLevel, msg["level"])
+			}
+
+			if msg["hclog_level"] != tt.level.String() {
+				t.Errorf("expected original level to be %q, got %v", tt.level.String(), msg["hclog_level"])
+			}
+		})
+	}
+
+	fired := map[string]struct {
+		rule *LevelRule
+		want uint64
+	}{
+		"failed to contact": {failedToContact, 2},
+		"suspect":           {suspect, 1},
+		"heartbeat":         {heartbeat, 1},
+		"unknown level":     {unknown, 0},
+	}
+
+	for name, tt := range fired {
+		if tt.rule.Fired() != tt.want {
+			t.Errorf("expected %s rule to fire %d times, got %d", name, tt.want, tt.rule.Fired())
+		}
+	}
+}
+
+func TestLevelRulesBeforeLevelCheck(t *testing.T) {
+	buf := &bytes.Buffer{}
+	root := NewWithOptions(
+		zerolog.New(buf),
+		WithLevel(hclog.Warn),
+		WithLevelRules(&LevelRule{Message: "entering leader state", Level: hclog.Warn}),
+	)
+
+	root.Info("entering leader state")
+
+	msg := &message{}
+	if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+		t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+	}
+
+	if msg.Level != "warn" {
+		t.Errorf("expected level to be %q, got %q", "warn", msg.Level)
+	}
+
+	buf.Reset()
+	root.Info("entering follower state")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected message to be dropped, got: %s", buf.String())
+	}
+}
+
+func TestLevelRulesWithoutLevel(t *testing.T) {
+	rule := &LevelRule{Message: "sending heartbeat"}
+
+	buf := &bytes.Buffer{}
+	root := NewWithOptions(zerolog.New(buf), WithLevel(hclog.Info), WithLevelRules(rule))
+
+	root.Debug("sending heartbeat")
+	root.Trace("sending heartbeat")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected messages to be dropped by the level check, got: %s", buf.String())
+	}
+
+	if rule.Fired() != 0 {
+		t.Errorf("expected the rule without level to be ignored, fired %d times", rule.Fired())
+	}
+}
@@ -0,0 +1,135 @@
+package hclogzerolog
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+// LevelSpecEnv is the environment variable conventionally holding the level spec, see [ParseLevelSpec].
+const LevelSpecEnv = "HCLOG_ZEROLOG_LEVEL"
+
+// ErrInvalidLevelSpec is returned by [ParseLevelSpec] for malformed specs.
+var ErrInvalidLevelSpec = errors.New("invalid level spec")
+
+// LevelSpec is the level of a logger family along with the levels of the names in it.
+type LevelSpec struct {
+	// Level is the level of the family, [hclog.NoLevel] keeps the level of the [Logger] as is.
+	Level hclog.Level
+	// Names are the levels by name prefix, see [WithNameLevel].
+	Names map[string]hclog.Level
+}
+
+// ParseLevelSpec parses the comma-separated list of levels, the one of the family
+// and the ones of the name prefixes, e.g.
+//
+//	info,raft=debug,raft.net=warn
+//
+// Both [hclog] and [zerolog] level names are accepted in any case,
+// [zerolog] levels are translated with the [DefaultLevelMapping],
+// so "fatal" and "panic" become [hclog.Error] and "disabled" becomes [hclog.Off].
+// Spaces around the entries and empty entries are ignored.
+//
+// Usually the spec is taken from the environment:
+//
+//	spec, err := hclogzerolog.ParseLevelSpec(os.Getenv(hclogzerolog.LevelSpecEnv))
+//	if err != nil {
+//		log.Fatal().Err(err).Msg("Bad log level")
+//	}
+//
+//	logger := hclogzerolog.NewWithOptions(log.Logger, hclogzerolog.WithLevelSpec(spec))
+func ParseLevelSpec(spec string) (LevelSpec, error) {
+	parsed := LevelSpec{Level: hclog.NoLevel, Names: make(map[string]hclog.Level)}
+	hasLevel := false
+
+	for entry := range strings.SplitSeq(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, levelName, named := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+
+		if !named {
+			levelName, name = name, ""
+		}
+
+		level, ok := parseLevel(levelName)
+		if !ok {
+			return LevelSpec{}, fmt.Errorf("%w: entry %q: unknown level %q", ErrInvalidLevelSpec, entry, strings.TrimSpace(levelName))
+		}
+
+		switch {
+		case !named && hasLevel:
+			return LevelSpec{}, fmt.Errorf("%w: entry %q: level of the family is already set", ErrInvalidLevelSpec, entry)
+		case !named:
+			parsed.Level, hasLevel = level, true
+		case name == "":
+			return LevelSpec{}, fmt.Errorf("%w: entry %q: empty name", ErrInvalidLevelSpec, entry)
+		default:
+			if _, ok := parsed.Names[name]; ok {
+				return LevelSpec{}, fmt.Errorf("%w: entry %q: level of %q is already set", ErrInvalidLevelSpec, entry, name)
+			}
+
+			parsed.Names[name] = level
+		}
+	}
+
+	return parsed, nil
+}
+
+// String formats the spec the way [ParseLevelSpec] parses it, the names sorted.
+func (s LevelSpec) String() string {
+	entries := make([]string, 0, len(s.Names)+1)
+
+	if s.Level != hclog.NoLevel {
+		entries = append(entries, s.Level.String())
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(s.Names)) {
+		entries = append(entries, name+"="+s.Names[name].String())
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// WithLevelSpec sets the level of the family and the levels of the names,
+// as [WithLevel] and [WithNameLevel] do.
+func WithLevelSpec(spec LevelSpec) Option {
+	return func(c *config) {
+		if spec.Level != hclog.NoLevel {
+			WithLevel(spec.Level)(c)
+		}
+
+		for name, level := range spec.Names {
+			WithNameLevel(name, level)(c)
+		}
+	}
+}
+
+// parseLevel parses [hclog] or [zerolog] level name.
+func parseLevel(name string) (hclog.Level, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return hclog.NoLevel, false
+	}
+
+	if level := hclog.LevelFromString(name); level != hclog.NoLevel {
+		return level, true
+	}
+
+	zerologLevel, err := zerolog.ParseLevel(name)
+	if err != nil {
+		return hclog.NoLevel, false
+	}
+
+	level, ok := DefaultLevelMapping().toHCLog(zerologLevel)
+
+	return level, ok && level != hclog.NoLevel
+}
@@ -0,0 +1,119 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	tests := []struct {
+		spec  string
+		level hclog.Level
+		names map[string]hclog.Level
+	}{
+		{"", hclog.NoLevel, map[string]hclog.Level{}},
+		{"info", hclog.Info, map[string]hclog.Level{}},
+		{"info,raft=debug,raft.net=warn", hclog.Info, map[string]hclog.Level{"raft": hclog.Debug, "raft.net": hclog.Warn}},
+		{" raft = DEBUG , , WARN ,", hclog.Warn, map[string]hclog.Level{"raft": hclog.Debug}},
+		{"raft=fatal,memberlist=disabled,serf=off", hclog.NoLevel, map[string]hclog.Level{
+			"raft": hclog.Error, "memberlist": hclog.Off, "serf": hclog.Off,
+		}},
+		{"-1,raft=panic", hclog.Trace, map[string]hclog.Level{"raft": hclog.Error}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := ParseLevelSpec(tt.spec)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if spec.Level != tt.level {
+				t.Errorf("expected level to be %v, got %v", tt.level, spec.Level)
+			}
+
+			if !maps.Equal(spec.Names, tt.names) {
+				t.Errorf("expected names to be %v, got %v", tt.names, spec.Names)
+			}
+		})
+	}
+}
+
+func TestParseLevelSpecErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		err  string
+	}{
+		{"verbose", `invalid level spec: entry "verbose": unknown level "verbose"`},
+		{"raft=", `invalid level spec: entry "raft=": unknown level ""`},
+		{"raft=loud", `invalid level spec: entry "raft=loud": unknown level "loud"`},
+		{"=debug", `invalid level spec: entry "=debug": empty name`},
+		{"info,warn", `invalid level spec: entry "warn": level of the family is already set`},
+		{"raft=info,raft=warn", `invalid level spec: entry "raft=warn": level of "raft" is already set`},
+		{"200", `invalid level spec: entry "200": unknown level "200"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseLevelSpec(tt.spec)
+
+			if !errors.Is(err, ErrInvalidLevelSpec) {
+				t.Fatalf("expected %v, got %v", ErrInvalidLevelSpec, err)
+			}
+
+			if err.Error() != tt.err {
+				t.Errorf("expected error to be %q, got %q", tt.err, err.Error())
+			}
+		})
+	}
+}
+
+func TestLevelSpecString(t *testing.T) {
+	spec, err := ParseLevelSpec("raft.net=error, Info, raft=WARN")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if spec.String() != "info,raft=warn,raft.net=error" {
+		t.Errorf("expected spec to be %q, got %q", "info,raft=warn,raft.net=error", spec.String())
+	}
+}
+
+func TestWithLevelSpec(t *testing.T) {
+	t.Setenv(LevelSpecEnv, "warn,raft=debug,raft.net=error")
+
+	spec, err := ParseLevelSpec(os.Getenv(LevelSpecEnv))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	root := NewWithOptions(zerolog.New(&bytes.Buffer{}).Level(zerolog.InfoLevel), WithLevelSpec(spec))
+
+	wants := map[string]hclog.Level{
+		"":             hclog.Warn,
+		"raft":         hclog.Debug,
+		"raft.net":     hclog.Error,
+		"raft.net.tcp": hclog.Error,
+		"memberlist":   hclog.Warn,
+	}
+
+	for name, want := range wants {
+		if level := root.ResetNamed(name).GetLevel(); level != want {
+			t.Errorf("expected level of %q to be %v, got %v", name, want, level)
+		}
+	}
+
+	t.Run("without level", func(t *testing.T) {
+		root := NewWithOptions(zerolog.New(&bytes.Buffer{}).Level(zerolog.InfoLevel), WithLevelSpec(LevelSpec{}))
+
+		if root.GetLevel() != hclog.Info {
+			t.Errorf("expected level to be kept, got %v", root.GetLevel())
+		}
+	})
+}
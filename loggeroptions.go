@@ -0,0 +1,90 @@
+package hclogzerolog
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+// FromLoggerOptions creates an instance of [Logger] wrapping provided [zerolog.Logger]
+// configured from [hclog.LoggerOptions], so it can replace [hclog.New] as is.
+//
+// The options are translated as follows:
+//   - Name is the initial name of the logger, see [WithName].
+//   - Level is the initial level, [hclog.NoLevel] means [hclog.DefaultLevel], like in [hclog].
+//   - Output replaces the writer of base. The events are written to it as JSON if JSONFormat is set,
+//     with [zerolog.ConsoleWriter] otherwise. If Output is nil, the writer of base is kept as is,
+//     and JSONFormat is ignored.
+//   - Mutex is held while the event is written.
+//   - IncludeLocation and AdditionalLocationOffset add the location of the caller to the events,
+//     see [WithLocation] and [WithAdditionalLocationOffset].
+//   - TimeFormat is the format of the timestamp added to the events unless DisableTime is set.
+//     If empty, the timestamp is formatted according to [zerolog.TimeFieldFormat].
+//   - Exclude drops the events it returns true for, see [WithExclude].
+//   - IndependentLevels and SyncParentLevel choose the [LevelMode].
+//
+// Other options are ignored. Since the timestamp is added by the wrapper,
+// base shouldn't have one of its own.
+func FromLoggerOptions(base zerolog.Logger, opts *hclog.LoggerOptions) *Logger {
+	if opts == nil {
+		opts = &hclog.LoggerOptions{}
+	}
+
+	if opts.Output != nil {
+		if opts.JSONFormat {
+			base = base.Output(opts.Output)
+		} else {
+			base = base.Output(zerolog.ConsoleWriter{
+				Out:     opts.Output,
+				NoColor: opts.Color == hclog.ColorOff,
+			})
+		}
+	}
+
+	if !opts.DisableTime {
+		if opts.TimeFormat == "" {
+			base = base.With().Timestamp().Logger()
+		} else {
+			base = base.Hook(timestampHook{format: opts.TimeFormat})
+		}
+	}
+
+	level := opts.Level
+	if level == hclog.NoLevel {
+		level = hclog.DefaultLevel
+	}
+
+	mode := SharedLevels
+
+	switch {
+	case opts.IndependentLevels:
+		mode = IndependentLevels
+	case opts.SyncParentLevel:
+		mode = SyncParentLevel
+	}
+
+	options := []Option{
+		WithName(opts.Name),
+		WithLevel(level),
+		WithLevelMode(mode),
+		WithAdditionalLocationOffset(opts.AdditionalLocationOffset),
+		WithExclude(opts.Exclude),
+		func(c *config) {
+			c.mutex = opts.Mutex
+		},
+	}
+
+	if opts.IncludeLocation {
+		options = append(options, WithLocation())
+	}
+
+	return NewWithOptions(base, options...)
+}
+
+// timestampHook adds the timestamp formatted with the given layout.
+type timestampHook struct {
+	format string
+}
+
+func (h timestampHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	e.Str(zerolog.TimestampFieldName, zerolog.TimestampFunc().Format(h.format))
+}
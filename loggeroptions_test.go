@@ -0,0 +1,220 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+type countingLocker struct {
+	locks   int
+	unlocks int
+}
+
+func (l *countingLocker) Lock() {
+	l.locks++
+}
+
+func (l *countingLocker) Unlock() {
+	l.unlocks++
+}
+
+func TestFromLoggerOptions(t *testing.T) {
+	t.Run("nil options", func(t *testing.T) {
+		hclogLogger := FromLoggerOptions(zerolog.New(&bytes.Buffer{}), nil)
+
+		if hclogLogger.GetLevel() != hclog.DefaultLevel {
+			t.Errorf("expected level to be %v, got %v", hclog.DefaultLevel, hclogLogger.GetLevel())
+		}
+
+		if hclogLogger.Name() != "" {
+			t.Errorf("expected name to be empty, got %q", hclogLogger.Name())
+		}
+	})
+
+	t.Run("name and level", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogLogger := FromLoggerOptions(zerolog.New(buf), &hclog.LoggerOptions{Name: "raft", Level: hclog.Warn})
+
+		hclogLogger.Info(messageToLog)
+
+		if buf.Len() != 0 {
+			t.Errorf("expected info message to be dropped, got: %s", buf.String())
+		}
+
+		hclogLogger.Warn(messageToLog)
+
+		if msg := decodeLine(t, buf); msg[DefaultNameField] != "raft" {
+			t.Errorf("expected %q field to be %q, got %v", DefaultNameField, "raft", msg[DefaultNameField])
+		}
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		base := &bytes.Buffer{}
+		output := &bytes.Buffer{}
+		hclogLogger := FromLoggerOptions(zerolog.New(base), &hclog.LoggerOptions{Output: output, JSONFormat: true})
+
+		hclogLogger.Info(messageToLog)
+
+		if base.Len() != 0 {
+			t.Errorf("expected nothing to be written to the base writer, got: %s", base.String())
+		}
+
+		msg := decodeLine(t, output)
+		if msg["message"] != messageToLog {
+			t.Errorf("expected message to be %q, got %v", messageToLog, msg["message"])
+		}
+
+		if _, ok := msg[zerolog.TimestampFieldName]; !ok {
+			t.Errorf("expected timestamp to be added, got: %s", output.String())
+		}
+	})
+
+	t.Run("console output", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		hclogLogger := FromLoggerOptions(zerolog.New(&bytes.Buffer{}), &hclog.LoggerOptions{Output: output})
+
+		hclogLogger.Info(messageToLog, customFieldName, customFieldValue)
+
+		if json.Valid(output.Bytes()) {
+			t.Errorf("expected console output, got JSON: %s", output.String())
+		}
+
+		for _, want := range []string{messageToLog, customFieldName + "=" + customFieldValue} {
+			if !strings.Contains(output.String(), want) {
+				t.Errorf("expected output to contain %q, got: %s", want, output.String())
+			}
+		}
+	})
+
+	t.Run("disable time", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogLogger := FromLoggerOptions(zerolog.New(buf), &hclog.LoggerOptions{DisableTime: true})
+
+		hclogLogger.Info(messageToLog)
+
+		if msg := decodeLine(t, buf); msg[zerolog.TimestampFieldName] != nil {
+			t.Errorf("expected no timestamp, got %v", msg[zerolog.TimestampFieldName])
+		}
+	})
+
+	t.Run("time format", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogLogger := FromLoggerOptions(zerolog.New(buf), &hclog.LoggerOptions{TimeFormat: time.DateOnly})
+
+		hclogLogger.Info(messageToLog)
+
+		timestamp, ok := decodeLine(t, buf)[zerolog.TimestampFieldName].(string)
+		if !ok {
+			t.Fatalf("expected timestamp to be a string, got: %s", buf.String())
+		}
+
+		if _, err := time.Parse(time.DateOnly, timestamp); err != nil {
+			t.Errorf("expected timestamp in %q format, got %q", time.DateOnly, timestamp)
+		}
+	})
+
+	t.Run("include location", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogLogger := FromLoggerOptions(zerolog.New(buf), &hclog.LoggerOptions{IncludeLocation: true})
+
+		_, file, line, _ := runtime.Caller(0)
+		hclogLogger.Info(messageToLog)
+
+		assertCaller(t, decodeLine(t, buf), file, line+1)
+
+		buf.Reset()
+
+		_, _, line, _ = runtime.Caller(0)
+		hclogLogger.Log(hclog.Info, messageToLog)
+
+		assertCaller(t, decodeLine(t, buf), file, line+1)
+	})
+
+	t.Run("additional location offset", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogLogger := FromLoggerOptions(zerolog.New(buf), &hclog.LoggerOptions{
+			IncludeLocation:          true,
+			AdditionalLocationOffset: 1,
+		})
+
+		logHelper := func() {
+			hclogLogger.Info(messageToLog)
+		}
+
+		_, file, line, _ := runtime.Caller(0)
+		logHelper()
+
+		assertCaller(t, decodeLine(t, buf), file, line+1)
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogLogger := FromLoggerOptions(zerolog.New(buf), &hclog.LoggerOptions{
+			Exclude: hclog.ExcludeByPrefix("heartbeat").Exclude,
+		})
+
+		hclogLogger.Error("heartbeat failed")
+
+		if buf.Len() != 0 {
+			t.Errorf("expected excluded message to be dropped, got: %s", buf.String())
+		}
+
+		hclogLogger.Error(messageToLog)
+
+		if buf.Len() == 0 {
+			t.Errorf("expected not excluded message to be written")
+		}
+	})
+
+	t.Run("level modes", func(t *testing.T) {
+		tests := []struct {
+			opts *hclog.LoggerOptions
+			mode LevelMode
+		}{
+			{&hclog.LoggerOptions{}, SharedLevels},
+			{&hclog.LoggerOptions{IndependentLevels: true}, IndependentLevels},
+			{&hclog.LoggerOptions{SyncParentLevel: true}, SyncParentLevel},
+		}
+
+		for _, tt := range tests {
+			hclogLogger := FromLoggerOptions(zerolog.New(&bytes.Buffer{}), tt.opts)
+
+			if hclogLogger.level.mode != tt.mode {
+				t.Errorf("expected level mode to be %v, got %v", tt.mode, hclogLogger.level.mode)
+			}
+		}
+	})
+
+	t.Run("mutex", func(t *testing.T) {
+		locker := &countingLocker{}
+		hclogLogger := FromLoggerOptions(zerolog.New(&bytes.Buffer{}), &hclog.LoggerOptions{Mutex: locker})
+
+		hclogLogger.Info(messageToLog)
+		hclogLogger.Named("raft").Warn(messageToLog)
+		hclogLogger.Debug(messageToLog)
+
+		if locker.locks != 2 || locker.unlocks != 2 {
+			t.Errorf("expected mutex to be locked and unlocked twice, got %d locks and %d unlocks", locker.locks, locker.unlocks)
+		}
+	})
+}
+
+func assertCaller(t *testing.T, msg map[string]any, file string, line int) {
+	t.Helper()
+
+	want := filepath.Base(file) + ":" + strconv.Itoa(line)
+
+	caller, _ := msg[zerolog.CallerFieldName].(string)
+	if !strings.HasSuffix(caller, want) {
+		t.Errorf("expected caller to end with %q, got %q", want, caller)
+	}
+}
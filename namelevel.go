@@ -0,0 +1,158 @@
+package hclogzerolog
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+// nameLevels is the set of the level rules by name prefix, shared by the family of loggers.
+// Loggers resolve their rule once they are named and again every time the rules change,
+// which is tracked by the version.
+type nameLevels struct {
+	mu         sync.RWMutex
+	rules      map[string]hclog.Level
+	version    atomic.Uint64
+	elevations map[string]*elevationStack[nameRule]
+}
+
+// resolvedLevel is the level rule resolved for a logger at the given version of the rules.
+type resolvedLevel struct {
+	version uint64
+	level   zerolog.Level
+	ok      bool
+}
+
+func (n *nameLevels) set(prefix string, level hclog.Level) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.rules == nil {
+		n.rules = make(map[string]hclog.Level)
+	}
+
+	n.rules[prefix] = level
+	n.version.Add(1)
+}
+
+func (n *nameLevels) reset(prefix string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.rules, prefix)
+	n.version.Add(1)
+}
+
+// get returns the level of the rule set for exactly the prefix.
+func (n *nameLevels) get(prefix string) (hclog.Level, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	level, ok := n.rules[prefix]
+
+	return level, ok
+}
+
+// elevationsOf returns the temporary levels of the prefix, see [Logger.SetNameLevelFor].
+func (n *nameLevels) elevationsOf(prefix string) *elevationStack[nameRule] {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.elevations == nil {
+		n.elevations = make(map[string]*elevationStack[nameRule])
+	}
+
+	stack, ok := n.elevations[prefix]
+	if !ok {
+		stack = &elevationStack[nameRule]{}
+		n.elevations[prefix] = stack
+	}
+
+	return stack
+}
+
+// snapshot returns a copy of the rules.
+func (n *nameLevels) snapshot() map[string]hclog.Level {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return maps.Clone(n.rules)
+}
+
+// resolve returns the level of the longest prefix the name matches, mapped with the mapping.
+// Rules with levels unknown to the mapping are skipped.
+func (n *nameLevels) resolve(name, separator string, mapping LevelMapping) *resolvedLevel {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	resolved := &resolvedLevel{version: n.version.Load()}
+
+	prefixes := matchingPrefixes(name, separator, n.rules)
+	for _, prefix := range slices.Backward(prefixes) {
+		if level, ok := mapping.toZerolog(n.rules[prefix]); ok {
+			resolved.level, resolved.ok = level, true
+
+			break
+		}
+	}
+
+	return resolved
+}
+
+// WithNameLevel sets the level of the loggers named prefix or descending from it,
+// like "raft" and "raft.net" for "raft" prefix. If several prefixes match the name,
+// the longest one wins, so
+//
+//	hclogzerolog.WithNameLevel("raft", hclog.Warn),
+//	hclogzerolog.WithNameLevel("raft.net", hclog.Error),
+//
+// makes "raft" and "raft.snapshot" loggers write warnings, but only errors for "raft.net".
+// The rules can be changed later with [Logger.SetNameLevel] and [Logger.ResetNameLevel].
+func WithNameLevel(prefix string, level hclog.Level) Option {
+	return func(c *config) {
+		c.nameLevels.set(prefix, level)
+	}
+}
+
+// SetNameLevel sets the level of the loggers of the family named prefix or descending from it,
+// see [WithNameLevel]. It's applied to the existing loggers as well as to the ones created later.
+//
+// The level set by the rule takes precedence over the one set with [Logger.SetLevel],
+// until the rule is removed with [Logger.ResetNameLevel].
+func (l *Logger) SetNameLevel(prefix string, level hclog.Level) {
+	if _, ok := l.mapping.toZerolog(level); !ok {
+		l.unknownLevel(level)
+
+		return
+	}
+
+	l.config.nameLevels.set(prefix, level)
+}
+
+// ResetNameLevel removes the level rule of the prefix set with [Logger.SetNameLevel] or [WithNameLevel],
+// so the loggers it matched are back to the level of a shorter prefix or their own level.
+func (l *Logger) ResetNameLevel(prefix string) {
+	l.config.nameLevels.reset(prefix)
+}
+
+// nameLevel returns the level of the rule matching the name of the logger, if any,
+// re-resolving it if the rules have changed since the last time.
+func (l *Logger) nameLevel() (zerolog.Level, bool) {
+	resolved := l.resolved.Load()
+	if resolved == nil || resolved.version != l.config.nameLevels.version.Load() {
+		resolved = l.resolveNameLevel()
+	}
+
+	return resolved.level, resolved.ok
+}
+
+func (l *Logger) resolveNameLevel() *resolvedLevel {
+	resolved := l.config.nameLevels.resolve(l.name, l.config.nameSeparator, l.mapping)
+	l.resolved.Store(resolved)
+
+	return resolved
+}
@@ -0,0 +1,167 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestNameLevel(t *testing.T) {
+	root := NewWithOptions(
+		zerolog.New(&bytes.Buffer{}),
+		WithLevel(hclog.Info),
+		WithNameLevel("raft", hclog.Warn),
+		WithNameLevel("raft.net", hclog.Error),
+		WithNameLevel("memberlist", hclog.Debug),
+	)
+
+	tests := []struct {
+		name  string
+		level hclog.Level
+	}{
+		{"", hclog.Info},
+		{"raft", hclog.Warn},
+		{"raft.snapshot", hclog.Warn},
+		{"raft.net", hclog.Error},
+		{"raft.net.tcp", hclog.Error},
+		{"rafting", hclog.Info},
+		{"memberlist", hclog.Debug},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := root.ResetNamed(tt.name)
+
+			if logger.GetLevel() != tt.level {
+				t.Errorf("expected level to be %v, got %v", tt.level, logger.GetLevel())
+			}
+		})
+	}
+
+	t.Run("Named", func(t *testing.T) {
+		logger := root.Named("raft").Named("net")
+
+		if logger.GetLevel() != hclog.Error {
+			t.Errorf("expected level to be %v, got %v", hclog.Error, logger.GetLevel())
+		}
+	})
+
+	t.Run("events", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := NewWithOptions(zerolog.New(buf), WithLevel(hclog.Trace), WithNameLevel("raft.net", hclog.Error))
+		netLogger := logger.Named("raft").Named("net")
+
+		netLogger.Warn(messageToLog)
+
+		if buf.Len() != 0 {
+			t.Errorf("expected warning to be dropped, got: %s", buf.String())
+		}
+
+		if netLogger.IsWarn() || !netLogger.IsError() {
+			t.Errorf("expected only errors to be enabled")
+		}
+
+		logger.Named("raft").Warn(messageToLog)
+
+		if buf.Len() == 0 {
+			t.Errorf("expected warning of another logger to be written")
+		}
+	})
+}
+
+func TestSetNameLevel(t *testing.T) {
+	root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevel(hclog.Info))
+	raft := root.named("raft")
+	raftNet := raft.named("net")
+
+	root.SetNameLevel("raft", hclog.Warn)
+
+	if raft.GetLevel() != hclog.Warn || raftNet.GetLevel() != hclog.Warn {
+		t.Errorf("expected existing loggers to follow the rule, got %v and %v", raft.GetLevel(), raftNet.GetLevel())
+	}
+
+	raftNet.SetNameLevel("raft.net", hclog.Error)
+
+	if raft.GetLevel() != hclog.Warn || raftNet.GetLevel() != hclog.Error {
+		t.Errorf("expected the longest prefix to win, got %v and %v", raft.GetLevel(), raftNet.GetLevel())
+	}
+
+	raft.SetLevel(hclog.Trace)
+
+	if raft.GetLevel() != hclog.Warn {
+		t.Errorf("expected the rule to take precedence over SetLevel, got %v", raft.GetLevel())
+	}
+
+	root.ResetNameLevel("raft.net")
+
+	if raftNet.GetLevel() != hclog.Warn {
+		t.Errorf("expected the shorter prefix to apply, got %v", raftNet.GetLevel())
+	}
+
+	root.ResetNameLevel("raft")
+
+	if raftNet.GetLevel() != hclog.Trace {
+		t.Errorf("expected own level to apply, got %v", raftNet.GetLevel())
+	}
+
+	t.Run("unknown level", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := New(zerolog.New(buf))
+
+		logger.SetNameLevel("raft", hclog.Level(999))
+
+		if logger.Named("raft").GetLevel() != hclog.Trace {
+			t.Errorf("expected unknown level to be ignored")
+		}
+
+		msg := &message{}
+		if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+		}
+
+		if msg.Level != "error" {
+			t.Errorf("expected unknown level to be reported as an error, got %q", msg.Level)
+		}
+	})
+
+	t.Run("global level", func(t *testing.T) {
+		setGlobalLevel(t, zerolog.ErrorLevel)
+
+		logger := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithNameLevel("raft", hclog.Debug))
+
+		if level := logger.Named("raft").GetLevel(); level != hclog.Error {
+			t.Errorf("expected global level to apply, got %v", level)
+		}
+	})
+}
+
+func TestNameLevelRace(t *testing.T) {
+	root := New(zerolog.New(io.Discard))
+	loggers := []*Logger{root, root.named("raft"), root.named("raft").named("net")}
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 100 {
+				logger := loggers[(i+j)%len(loggers)]
+				logger.SetNameLevel("raft", hclog.Level(1+j%5))
+				logger.Named("tcp").Info(messageToLog)
+				logger.Warn(messageToLog)
+				_ = logger.GetLevel()
+				logger.ResetNameLevel("raft")
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,312 @@
+package hclogzerolog
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+// DefaultNameSeparator — separator [Logger.Named] joins the parent and the sublogger names with,
+// same as in [hclog].
+const DefaultNameSeparator = "."
+
+// Option configures the [Logger] created with [NewWithOptions].
+// Options are shared by the whole family of loggers derived from it.
+type Option func(*config)
+
+type config struct {
+	nameField     string
+	nameSeparator string
+	name          string
+	level         *hclog.Level
+	levelMode     LevelMode
+	levelMapping  LevelMapping
+	// nameMappings are the mappings set with [WithNameLevelMapping], by name prefix
+	nameMappings map[string]LevelMapping
+	levelField   string
+	nameLevels   *nameLevels
+	registry     *Registry
+	levelRules   []*LevelRule
+	collapser    *collapser
+	nameSamplers map[string]zerolog.Sampler
+	recorder     *flightRecorder
+
+	// rateLimits and rateLimitInterval are the settings the rateLimiter is created with
+	rateLimits        map[hclog.Level]RateLimit
+	rateLimitInterval time.Duration
+	rateLimiter       *rateLimiter
+
+	includeLocation bool
+	locationOffset  int
+	exclude         func(level hclog.Level, msg string, args ...any) bool
+	mutex           hclog.Locker
+
+	stdSubsystems bool
+	stdKeyValues  bool
+
+	sinks *sinkSet
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		nameField:     DefaultNameField,
+		nameSeparator: DefaultNameSeparator,
+		levelMapping:  DefaultLevelMapping(),
+		nameLevels:    &nameLevels{},
+
+		rateLimitInterval: DefaultRateLimitSummaryInterval,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	cfg.rateLimiter = newRateLimiter(cfg.rateLimits, cfg.rateLimitInterval)
+
+	return cfg
+}
+
+// levelMappingFor returns the mapping of the logger with the given name.
+func (c *config) levelMappingFor(name string) LevelMapping {
+	prefixes := matchingPrefixes(name, c.nameSeparator, c.nameMappings)
+	if len(prefixes) == 0 {
+		return c.levelMapping
+	}
+
+	mapping := maps.Clone(c.levelMapping)
+	for _, prefix := range prefixes {
+		maps.Copy(mapping, c.nameMappings[prefix])
+	}
+
+	return mapping
+}
+
+// matchingPrefixes returns the prefixes, the keys of the map, the name equals to or descends from, the shortest first.
+func matchingPrefixes[V any](name, separator string, prefixes map[string]V) []string {
+	var matching []string
+
+	for prefix := range prefixes {
+		if hasNamePrefix(name, prefix, separator) {
+			matching = append(matching, prefix)
+		}
+	}
+
+	slices.SortFunc(matching, func(a, b string) int {
+		return len(a) - len(b)
+	})
+
+	return matching
+}
+
+// hasNamePrefix reports whether the name equals to the prefix or descends from it.
+func hasNamePrefix(name, prefix, separator string) bool {
+	return name == prefix || strings.HasPrefix(name, prefix+separator)
+}
+
+// WithNameField sets the field (key) the [hclog.Logger] name will be written to.
+// Default is [DefaultNameField].
+func WithNameField(nameField string) Option {
+	return func(c *config) {
+		c.nameField = nameField
+	}
+}
+
+// WithNameSeparator sets the separator [Logger.Named] joins the names with.
+// Default is [DefaultNameSeparator].
+func WithNameSeparator(separator string) Option {
+	return func(c *config) {
+		c.nameSeparator = separator
+	}
+}
+
+// WithName sets the initial name of the logger, as if [Logger.ResetNamed] was called.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithLevel sets the initial level of the logger.
+// By default, the level of the wrapped [zerolog.Logger] is used.
+// Unknown levels are ignored.
+func WithLevel(level hclog.Level) Option {
+	return func(c *config) {
+		c.level = &level
+	}
+}
+
+// WithLevelMode sets how the level is shared with the derived loggers.
+// Default is [SharedLevels].
+func WithLevelMode(mode LevelMode) Option {
+	return func(c *config) {
+		c.levelMode = mode
+	}
+}
+
+// WithLevelMapping overrides the [zerolog] levels the [hclog] levels are mapped to.
+// Levels missing in the mapping keep their [DefaultLevelMapping].
+//
+// For example, to write [hclog.Info] messages at [zerolog.DebugLevel]:
+//
+//	hclogzerolog.WithLevelMapping(hclogzerolog.LevelMapping{hclog.Info: zerolog.DebugLevel})
+func WithLevelMapping(mapping LevelMapping) Option {
+	return func(c *config) {
+		maps.Copy(c.levelMapping, mapping)
+	}
+}
+
+// WithNameLevelMapping overrides the [zerolog] levels the [hclog] levels are mapped to
+// for the loggers named prefix or descending from it, like "raft" and "raft.net" for "raft" prefix.
+// Levels missing in the mapping keep the mapping of the shorter matching prefix, if any,
+// or of the family, see [WithLevelMapping].
+//
+// The mapping is resolved once the logger is named, and it's used by the logging methods,
+// [Logger.SetLevel], [Logger.GetLevel] and the IsX methods of the logger.
+// Keep in mind the level is a [zerolog] threshold shared according to [LevelMode],
+// so the loggers with different mappings may report the same threshold as different [hclog] levels.
+//
+// For example, to write Info messages of raft at [zerolog.DebugLevel]:
+//
+//	hclogzerolog.WithNameLevelMapping("raft", hclogzerolog.LevelMapping{hclog.Info: zerolog.DebugLevel})
+func WithNameLevelMapping(prefix string, mapping LevelMapping) Option {
+	return func(c *config) {
+		if c.nameMappings == nil {
+			c.nameMappings = make(map[string]LevelMapping)
+		}
+
+		merged := make(LevelMapping, len(mapping))
+		maps.Copy(merged, c.nameMappings[prefix])
+		maps.Copy(merged, mapping)
+
+		c.nameMappings[prefix] = merged
+	}
+}
+
+// WithLevelField writes the [hclog] level of the messages to the given field,
+// e.g. "hclog_level": "info", so it's preserved when the level is remapped with
+// [WithLevelMapping], [WithNameLevelMapping] or [WithLevelRules]. Messages with no level don't get the field.
+func WithLevelField(field string) Option {
+	return func(c *config) {
+		c.levelField = field
+	}
+}
+
+// WithExclude drops the messages the exclude function returns true for,
+// like [hclog.LoggerOptions] Exclude does. It's called once the level check is passed,
+// before anything is built for the event, so dropping a message is cheap.
+// Options given several times are combined: a message is dropped if any of the functions returns true.
+//
+// The excluders of [hclog] can be used as is:
+//
+//	hclogzerolog.WithExclude(hclog.ExcludeByPrefix("heartbeat").Exclude)
+//	hclogzerolog.WithExclude(hclog.ExcludeByRegexp{Regexp: regexp.MustCompile("^failed to contact")}.Exclude)
+func WithExclude(exclude func(level hclog.Level, msg string, args ...any) bool) Option {
+	return func(c *config) {
+		if exclude == nil {
+			return
+		}
+
+		previous := c.exclude
+		if previous == nil {
+			c.exclude = exclude
+
+			return
+		}
+
+		c.exclude = func(level hclog.Level, msg string, args ...any) bool {
+			return previous(level, msg, args...) || exclude(level, msg, args...)
+		}
+	}
+}
+
+// WithLocation adds the location (file:line) of the caller of the [Logger] to the messages,
+// under the [zerolog.CallerFieldName] key, like [hclog.LoggerOptions] IncludeLocation does.
+//
+// The location reported is the one of the code calling the [Logger], e.g. raft sources,
+// not of the wrapper. It's true for the [zerolog.Context.Caller] of the wrapped logger as well,
+// so either of them can be used.
+func WithLocation() Option {
+	return func(c *config) {
+		c.includeLocation = true
+	}
+}
+
+// WithAdditionalLocationOffset sets the number of additional frames to skip when the location of
+// the caller is reported, see [WithLocation]. It's useful when the [Logger] is wrapped again,
+// like [hclog.LoggerOptions] AdditionalLocationOffset.
+func WithAdditionalLocationOffset(offset int) Option {
+	return func(c *config) {
+		c.locationOffset = offset
+	}
+}
+
+// WithStandardSubsystems makes the writers returned by [Logger.StandardWriter] and [Logger.StandardLogger]
+// recognize the "subsystem: " prefix of the lines, like libraries as memberlist, serf or yamux write.
+// The prefix is stripped off and appended to the name of the logger, like [Logger.Named] does.
+//
+//	[DEBUG] memberlist: Stream connection from=10.0.0.2:7946
+//
+// is written by the logger named "raft" as the "Stream connection from=10.0.0.2:7946" message
+// of the "raft.memberlist" logger.
+func WithStandardSubsystems() Option {
+	return func(c *config) {
+		c.stdSubsystems = true
+	}
+}
+
+// WithStandardKeyValues makes the writers returned by [Logger.StandardWriter] and [Logger.StandardLogger]
+// parse the trailing key=value pairs of the lines into fields. The values can be quoted.
+//
+//	[DEBUG] memberlist: Stream connection from=10.0.0.2:7946
+//
+// is written as the "memberlist: Stream connection" message with the "from" field.
+func WithStandardKeyValues() Option {
+	return func(c *config) {
+		c.stdKeyValues = true
+	}
+}
+
+// NewWithOptions creates an instance of [Logger] wrapping provided [zerolog.Logger]
+// configured with the given options.
+//
+//	raftLogger := log.With().Str("component", "raft").Logger()
+//	config := raft.DefaultConfig()
+//	config.Logger = hclogzerolog.NewWithOptions(
+//		raftLogger,
+//		hclogzerolog.WithNameField("subsystem"),
+//		hclogzerolog.WithLevel(hclog.Info),
+//	)
+func NewWithOptions(logger zerolog.Logger, opts ...Option) *Logger {
+	cfg := newConfig(opts)
+
+	mapping := cfg.levelMappingFor(cfg.name)
+
+	level := logger.GetLevel()
+	if cfg.level != nil {
+		if mapped, ok := mapping.toZerolog(*cfg.level); ok {
+			level = mapped
+		}
+	}
+
+	base := logger.Level(permissiveLevel)
+	root := &Logger{
+		base:   base,
+		config: cfg,
+		level:  newLevelHolder(level, cfg.levelMode),
+	}
+
+	root.name = cfg.name
+	root.mapping = mapping
+	root.levelRules = cfg.levelRulesFor(cfg.name, mapping)
+	root.sampler = cfg.samplerFor(cfg.name)
+	root.resolveNameLevel()
+
+	root.register()
+	root.logger = root.context(cfg.name, nil)
+
+	return root
+}
@@ -0,0 +1,439 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		hclogLogger := NewWithOptions(zerolog.New(&bytes.Buffer{}).Level(zerolog.WarnLevel))
+
+		if hclogLogger.config.nameField != DefaultNameField {
+			t.Errorf("expected nameField to be %q, got %q", DefaultNameField, hclogLogger.config.nameField)
+		}
+
+		if hclogLogger.config.nameSeparator != DefaultNameSeparator {
+			t.Errorf("expected nameSeparator to be %q, got %q", DefaultNameSeparator, hclogLogger.config.nameSeparator)
+		}
+
+		if hclogLogger.Name() != "" {
+			t.Errorf("expected name to be empty, got %q", hclogLogger.Name())
+		}
+
+		if hclogLogger.GetLevel() != hclog.Warn {
+			t.Errorf("expected level to be taken from the zerolog logger, got %v", hclogLogger.GetLevel())
+		}
+	})
+
+	t.Run("name field and name", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogLogger := NewWithOptions(zerolog.New(buf), WithNameField("subsystem"), WithName("raft"))
+
+		hclogLogger.Info(messageToLog)
+
+		var msg map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+		}
+
+		if msg["subsystem"] != "raft" {
+			t.Errorf("expected %q field to be %q, got %v", "subsystem", "raft", msg["subsystem"])
+		}
+
+		if hclogLogger.Name() != "raft" {
+			t.Errorf("expected name to be %q, got %q", "raft", hclogLogger.Name())
+		}
+	})
+
+	t.Run("name separator", func(t *testing.T) {
+		hclogLogger := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithName("raft"), WithNameSeparator("/"))
+
+		if name := hclogLogger.Named("net").Named("tcp").Name(); name != "raft/net/tcp" {
+			t.Errorf("expected name to be %q, got %q", "raft/net/tcp", name)
+		}
+	})
+
+	t.Run("level", func(t *testing.T) {
+		hclogLogger := NewWithOptions(zerolog.New(&bytes.Buffer{}).Level(zerolog.WarnLevel), WithLevel(hclog.Debug))
+
+		if hclogLogger.GetLevel() != hclog.Debug {
+			t.Errorf("expected level to be %v, got %v", hclog.Debug, hclogLogger.GetLevel())
+		}
+	})
+
+	t.Run("unknown level is ignored", func(t *testing.T) {
+		hclogLogger := NewWithOptions(zerolog.New(&bytes.Buffer{}).Level(zerolog.WarnLevel), WithLevel(hclog.Level(999)))
+
+		if hclogLogger.GetLevel() != hclog.Warn {
+			t.Errorf("expected level to be %v, got %v", hclog.Warn, hclogLogger.GetLevel())
+		}
+	})
+
+	t.Run("level mode", func(t *testing.T) {
+		hclogLogger := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevelMode(IndependentLevels))
+
+		if hclogLogger.level.mode != IndependentLevels {
+			t.Errorf("expected level mode to be %v, got %v", IndependentLevels, hclogLogger.level.mode)
+		}
+	})
+}
+
+func TestWithLevelMapping(t *testing.T) {
+	buf := &bytes.Buffer{}
+	hclogLogger := NewWithOptions(
+		zerolog.New(buf).Level(zerolog.InfoLevel),
+		WithLevelMapping(LevelMapping{hclog.Info: zerolog.DebugLevel, hclog.Error: zerolog.WarnLevel}),
+	)
+
+	hclogLogger.Info(messageToLog)
+
+	if buf.Len() != 0 {
+		t.Errorf("expected info message mapped to debug to be dropped, got: %s", buf.String())
+	}
+
+	if hclogLogger.IsInfo() {
+		t.Errorf("expected IsInfo to return false")
+	}
+
+	hclogLogger.Log(hclog.Error, messageToLog)
+
+	msg := &message{}
+	if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+		t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+	}
+
+	if msg.Level != "warn" {
+		t.Errorf("expected error message to be written at warn level, got %q", msg.Level)
+	}
+
+	hclogLogger.SetLevel(hclog.Info)
+
+	if hclogLogger.level.get() != zerolog.DebugLevel {
+		t.Errorf("expected SetLevel to use the mapping, got %v", hclogLogger.level.get())
+	}
+
+	if !hclogLogger.IsInfo() {
+		t.Errorf("expected IsInfo to return true")
+	}
+
+	if DefaultLevelMapping()[hclog.Info] != zerolog.InfoLevel {
+		t.Errorf("expected default mapping to stay untouched")
+	}
+}
+
+func TestWithNameLevelMapping(t *testing.T) {
+	buf := &bytes.Buffer{}
+	hclogLogger := NewWithOptions(
+		zerolog.New(buf).Level(zerolog.InfoLevel),
+		WithNameLevelMapping("raft", LevelMapping{hclog.Info: zerolog.DebugLevel, hclog.Error: zerolog.WarnLevel}),
+		WithNameLevelMapping("raft.net", LevelMapping{hclog.Info: zerolog.TraceLevel}),
+	)
+
+	tests := []struct {
+		name       string
+		level      hclog.Level
+		wantLevel  string
+		wantLogged bool
+	}{
+		{"", hclog.Info, "info", true},
+		{"raft", hclog.Info, "", false},
+		{"raft", hclog.Error, "warn", true},
+		{"raft.net", hclog.Info, "", false},
+		{"raft.net", hclog.Error, "warn", true},
+		{"raft.net.tcp", hclog.Error, "warn", true},
+		{"rafting", hclog.Error, "error", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.level.String(), func(t *testing.T) {
+			buf.Reset()
+
+			logger := hclogLogger.ResetNamed(tt.name)
+			logger.Log(tt.level, messageToLog)
+
+			if !tt.wantLogged {
+				if buf.Len() != 0 {
+					t.Errorf("expected message to be dropped, got: %s", buf.String())
+				}
+
+				return
+			}
+
+			msg := &message{}
+			if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+				t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+			}
+
+			if msg.Level != tt.wantLevel {
+				t.Errorf("expected level to be %q, got %q", tt.wantLevel, msg.Level)
+			}
+		})
+	}
+
+	t.Run("level methods", func(t *testing.T) {
+		raftLogger := hclogLogger.Named("raft")
+
+		if raftLogger.IsInfo() {
+			t.Errorf("expected IsInfo to return false")
+		}
+
+		if raftLogger.GetLevel() != hclog.Warn {
+			t.Errorf("expected level to be %v, got %v", hclog.Warn, raftLogger.GetLevel())
+		}
+
+		raftLogger.SetLevel(hclog.Info)
+		defer raftLogger.SetLevel(hclog.Info)
+
+		if hclogLogger.level.get() != zerolog.DebugLevel {
+			t.Errorf("expected SetLevel to use the mapping of the name, got %v", hclogLogger.level.get())
+		}
+
+		if !raftLogger.IsInfo() {
+			t.Errorf("expected IsInfo to return true")
+		}
+
+		if hclogLogger.GetLevel() != hclog.Debug {
+			t.Errorf("expected unnamed logger to report %v, got %v", hclog.Debug, hclogLogger.GetLevel())
+		}
+	})
+
+	t.Run("initial name", func(t *testing.T) {
+		hclogLogger := NewWithOptions(
+			zerolog.New(&bytes.Buffer{}),
+			WithName("raft"),
+			WithLevel(hclog.Info),
+			WithNameLevelMapping("raft", LevelMapping{hclog.Info: zerolog.DebugLevel}),
+		)
+
+		if hclogLogger.level.get() != zerolog.DebugLevel {
+			t.Errorf("expected initial level to use the mapping of the name, got %v", hclogLogger.level.get())
+		}
+	})
+}
+
+func TestWithLevelField(t *testing.T) {
+	buf := &bytes.Buffer{}
+	hclogLogger := NewWithOptions(
+		zerolog.New(buf),
+		WithLevelField("hclog_level"),
+		WithLevelMapping(LevelMapping{hclog.Info: zerolog.DebugLevel}),
+	)
+
+	hclogLogger.Info(messageToLog)
+
+	msg := decodeLine(t, buf)
+	if msg["level"] != "debug" || msg["hclog_level"] != "info" {
+		t.Errorf("expected debug level and info hclog level, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	hclogLogger.Info(messageToLog, "hclog_level", customFieldValue)
+
+	if occurrences := keyOccurrences(t, buf.Bytes()); occurrences["hclog_level"] != 1 {
+		t.Errorf("expected the field to be written once, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	hclogLogger.Log(hclog.NoLevel, messageToLog)
+
+	if msg := decodeLine(t, buf); msg["hclog_level"] != nil {
+		t.Errorf("expected no hclog level for the message with no level, got: %s", buf.String())
+	}
+}
+
+func TestLevelMappingToHCLog(t *testing.T) {
+	mapping := DefaultLevelMapping()
+	mapping[hclog.Info] = zerolog.DebugLevel
+
+	tests := []struct {
+		zerologLevel zerolog.Level
+		hclogLevel   hclog.Level
+		ok           bool
+	}{
+		{zerolog.TraceLevel, hclog.Trace, true},
+		{zerolog.DebugLevel, hclog.Debug, true},
+		{zerolog.InfoLevel, hclog.Warn, true},
+		{zerolog.WarnLevel, hclog.Warn, true},
+		{zerolog.FatalLevel, hclog.Error, true},
+		{zerolog.NoLevel, hclog.NoLevel, true},
+		{zerolog.Disabled, hclog.Off, true},
+		{zerolog.Level(-2), hclog.NoLevel, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zerologLevel.String(), func(t *testing.T) {
+			level, ok := mapping.toHCLog(tt.zerologLevel)
+
+			if level != tt.hclogLevel || ok != tt.ok {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.hclogLevel, tt.ok, level, ok)
+			}
+		})
+	}
+}
+
+func TestWithLocation(t *testing.T) {
+	logCalls := map[string]func(l *Logger) int{
+		"Log": func(l *Logger) int {
+			_, _, line, _ := runtime.Caller(0)
+			l.Log(hclog.Info, messageToLog)
+
+			return line + 1
+		},
+		"Info": func(l *Logger) int {
+			_, _, line, _ := runtime.Caller(0)
+			l.Info(messageToLog)
+
+			return line + 1
+		},
+		"Error": func(l *Logger) int {
+			_, _, line, _ := runtime.Caller(0)
+			l.Error(messageToLog)
+
+			return line + 1
+		},
+		"unknown level": func(l *Logger) int {
+			_, _, line, _ := runtime.Caller(0)
+			l.Log(hclog.Level(999), messageToLog)
+
+			return line + 1
+		},
+		"StandardLogger Println": func(l *Logger) int {
+			stdLogger := l.StandardLogger(nil)
+
+			_, _, line, _ := runtime.Caller(0)
+			stdLogger.Println(messageToLog)
+
+			return line + 1
+		},
+		"StandardLogger Printf": func(l *Logger) int {
+			stdLogger := l.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true})
+
+			_, _, line, _ := runtime.Caller(0)
+			stdLogger.Printf("[WARN] %s", messageToLog)
+
+			return line + 1
+		},
+		"StandardLogger Output": func(l *Logger) int {
+			stdLogger := l.StandardLogger(nil)
+
+			_, _, line, _ := runtime.Caller(0)
+			_ = stdLogger.Output(1, messageToLog)
+
+			return line + 1
+		},
+		"StandardWriter": func(l *Logger) int {
+			writer := l.StandardWriter(nil)
+
+			_, _, line, _ := runtime.Caller(0)
+			_, _ = writer.Write([]byte(messageToLog))
+
+			return line + 1
+		},
+	}
+
+	_, file, _, _ := runtime.Caller(0)
+
+	for name, logCall := range logCalls {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			hclogLogger := NewWithOptions(zerolog.New(buf), WithLocation())
+
+			line := logCall(hclogLogger)
+
+			assertCaller(t, decodeLine(t, buf), file, line)
+		})
+
+		t.Run(name+"/zerolog caller", func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			hclogLogger := New(zerolog.New(buf).With().Caller().Logger())
+
+			line := logCall(hclogLogger)
+
+			assertCaller(t, decodeLine(t, buf), file, line)
+		})
+	}
+
+	t.Run("additional offset", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		hclogLogger := NewWithOptions(zerolog.New(buf), WithLocation(), WithAdditionalLocationOffset(1))
+
+		logHelper := func(msg string) {
+			hclogLogger.Warn(msg)
+		}
+
+		_, _, line, _ := runtime.Caller(0)
+		logHelper(messageToLog)
+
+		assertCaller(t, decodeLine(t, buf), file, line+1)
+	})
+}
+
+func TestWithExclude(t *testing.T) {
+	messages := &hclog.ExcludeByMessage{}
+	messages.Add("heartbeat timeout")
+
+	buf := &bytes.Buffer{}
+	hclogLogger := NewWithOptions(
+		zerolog.New(buf),
+		WithExclude(hclog.ExcludeByPrefix("skipping").Exclude),
+		WithExclude(messages.Exclude),
+		WithExclude(hclog.ExcludeByRegexp{Regexp: regexp.MustCompile(`^failed to contact \d+`)}.Exclude),
+		WithExclude(nil),
+		WithExclude(func(level hclog.Level, _ string, args ...any) bool {
+			return level == hclog.Debug && hasKey(args, "peer")
+		}),
+	)
+
+	tests := []struct {
+		level    hclog.Level
+		msg      string
+		args     []any
+		excluded bool
+	}{
+		{hclog.Info, "skipping snapshot", nil, true},
+		{hclog.Warn, "heartbeat timeout", nil, true},
+		{hclog.Warn, "heartbeat timeout reached", nil, false},
+		{hclog.Error, "failed to contact 10.0.0.1", nil, true},
+		{hclog.Error, "failed to contact quorum", nil, false},
+		{hclog.Debug, messageToLog, []any{"peer", "node1"}, true},
+		{hclog.Info, messageToLog, []any{"peer", "node1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			buf.Reset()
+
+			hclogLogger.Log(tt.level, tt.msg, tt.args...)
+
+			if excluded := buf.Len() == 0; excluded != tt.excluded {
+				t.Errorf("expected excluded to be %v, got output: %s", tt.excluded, buf.String())
+			}
+		})
+	}
+
+	t.Run("standard writer", func(t *testing.T) {
+		buf.Reset()
+
+		_, _ = hclogLogger.StandardWriter(&hclog.StandardLoggerOptions{InferLevels: true}).Write([]byte("[INFO] skipping snapshot"))
+
+		if buf.Len() != 0 {
+			t.Errorf("expected excluded line to be dropped, got: %s", buf.String())
+		}
+	})
+	t.Run("no allocations", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			hclogLogger.Info("skipping snapshot")
+		})
+
+		if allocs != 0 {
+			t.Errorf("expected excluded message not to allocate, got %v allocations", allocs)
+		}
+	})
+}
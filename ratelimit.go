@@ -0,0 +1,198 @@
+package hclogzerolog
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// DefaultRateLimitSummaryInterval — how long the messages are suppressed by the rate limit
+// before the summary is written, see [WithRateLimitSummaryInterval].
+const DefaultRateLimitSummaryInterval = 10 * time.Second
+
+// minRateLimiterPruneSize is the number of the messages the rate limiter tracks before it starts
+// to drop the idle ones.
+const minRateLimiterPruneSize = 1024
+
+// RateLimit is the token bucket limiting the rate of the messages, see [WithRateLimit].
+type RateLimit struct {
+	// Rate is the number of the messages allowed per second in the long run
+	Rate float64
+	// Burst is the number of the messages allowed at once
+	Burst int
+}
+
+// WithRateLimit limits the rate of the messages of the level. Every distinct message,
+// identified by the name of the logger, the level and the message itself, has its own token bucket,
+// so a flood of one message doesn't affect the others.
+//
+// The messages exceeding the limit are dropped. Once [DefaultRateLimitSummaryInterval] elapses since
+// the first message dropped, a summary is written at the same level instead, like
+//
+//	{"level":"error","hclog_name":"raft","peer":"node2","suppressed":312,"suppressed_message":"failed to heartbeat to","message":"suppressed 312 similar messages"}
+//
+// carrying the args and, with [WithLocation], the location of the last message dropped.
+// The limit applies to the messages passing the level check and [WithExclude], after [WithLevelRules],
+// so the level is the rewritten one.
+//
+//	hclogzerolog.WithRateLimit(hclog.Error, hclogzerolog.RateLimit{Rate: 1, Burst: 10})
+func WithRateLimit(level hclog.Level, limit RateLimit) Option {
+	return func(c *config) {
+		if c.rateLimits == nil {
+			c.rateLimits = make(map[hclog.Level]RateLimit)
+		}
+
+		c.rateLimits[level] = limit
+	}
+}
+
+// WithRateLimitSummaryInterval sets how long the messages are suppressed by [WithRateLimit]
+// before the summary is written. Default is [DefaultRateLimitSummaryInterval].
+// It has no effect without [WithRateLimit].
+func WithRateLimitSummaryInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.rateLimitInterval = interval
+	}
+}
+
+// newRateLimiter creates the rate limiter of the limits set with [WithRateLimit], if any.
+func newRateLimiter(limits map[hclog.Level]RateLimit, interval time.Duration) *rateLimiter {
+	if limits == nil {
+		return nil
+	}
+
+	return &rateLimiter{limits: limits, interval: interval, pruneAt: minRateLimiterPruneSize}
+}
+
+// rateLimiter tracks the token buckets of the messages of a family.
+type rateLimiter struct {
+	limits   map[hclog.Level]RateLimit
+	interval time.Duration
+
+	mu      sync.Mutex
+	buckets map[rateKey]*rateBucket
+	pruneAt int
+}
+
+type rateKey struct {
+	name  string
+	level hclog.Level
+	msg   string
+}
+
+// rateBucket is the token bucket of a message along with the suppressed messages.
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+
+	suppressed int
+	// logger, caller, original and args are the ones of the last message suppressed
+	logger   *Logger
+	caller   string
+	original hclog.Level
+	args     []any
+	// summary is the timer writing the summary, nil if nothing is suppressed
+	summary *time.Timer
+}
+
+// allow takes a token of the message. If there is none, the message is suppressed
+// and the summary is scheduled.
+func (r *rateLimiter) allow(logger *Logger, caller string, level, original hclog.Level, msg string, args []any) bool {
+	limit, ok := r.limits[level]
+	if !ok {
+		return true
+	}
+
+	now := time.Now()
+	key := rateKey{logger.name, level, msg}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		if len(r.buckets) >= r.pruneAt {
+			r.prune(now)
+		}
+
+		if r.buckets == nil {
+			r.buckets = make(map[rateKey]*rateBucket)
+		}
+
+		bucket = &rateBucket{tokens: float64(limit.Burst), last: now}
+		r.buckets[key] = bucket
+	}
+
+	if bucket.take(now, limit) {
+		return true
+	}
+
+	bucket.suppressed++
+	bucket.logger, bucket.caller, bucket.original, bucket.args = logger, caller, original, slices.Clone(args)
+
+	if bucket.summary == nil {
+		bucket.summary = time.AfterFunc(r.interval, func() {
+			r.summarize(key)
+		})
+	}
+
+	return false
+}
+
+// summarize writes the summary of the messages suppressed.
+func (r *rateLimiter) summarize(key rateKey) {
+	r.mu.Lock()
+
+	bucket, ok := r.buckets[key]
+	if !ok || bucket.suppressed == 0 {
+		r.mu.Unlock()
+
+		return
+	}
+
+	suppressed, logger, caller, original, args := bucket.suppressed, bucket.logger, bucket.caller, bucket.original, bucket.args
+	bucket.suppressed, bucket.logger, bucket.args, bucket.summary = 0, nil, nil, nil
+
+	r.mu.Unlock()
+
+	if !logger.enabled(logger.mapping[key.level]) {
+		return
+	}
+
+	args = append(args, "suppressed", suppressed, "suppressed_message", key.msg)
+	logger.write(0, caller, key.level, original, fmt.Sprintf("suppressed %d similar messages", suppressed), args)
+}
+
+// prune drops the buckets which are full and have nothing suppressed. The caller must hold the mutex.
+func (r *rateLimiter) prune(now time.Time) {
+	for key, bucket := range r.buckets {
+		limit := r.limits[key.level]
+		if bucket.summary == nil && bucket.refill(now, limit) >= float64(limit.Burst) {
+			delete(r.buckets, key)
+		}
+	}
+
+	r.pruneAt = max(2*len(r.buckets), minRateLimiterPruneSize)
+}
+
+// take refills the bucket and takes a token out of it, if there is one.
+func (b *rateBucket) take(now time.Time, limit RateLimit) bool {
+	b.tokens = b.refill(now, limit)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+// refill returns the number of tokens in the bucket at the time.
+func (b *rateBucket) refill(now time.Time, limit RateLimit) float64 {
+	return min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+}
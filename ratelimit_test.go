@@ -0,0 +1,171 @@
+package hclogzerolog
+
+import (
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(
+		zerolog.New(buf),
+		WithRateLimit(hclog.Error, RateLimit{Rate: 0.001, Burst: 2}),
+		WithRateLimitSummaryInterval(50*time.Millisecond),
+	)
+	raft := root.Named("raft")
+
+	for i := range 5 {
+		raft.Error("failed to heartbeat to", "peer", "node"+strconv.Itoa(i))
+	}
+
+	raft.Error("failed to appendEntries to", "peer", "node1")
+	root.Named("memberlist").Error("failed to heartbeat to", "peer", "node1")
+	raft.Warn("failed to heartbeat to", "peer", "node1")
+
+	if lines := buf.lines(); len(lines) != 5 {
+		t.Fatalf("expected 5 messages to be written, got: %v", lines)
+	}
+
+	waitFor(t, func() bool { return len(buf.lines()) == 6 })
+
+	summary := decodeLines(t, buf)[5]
+
+	want := map[string]any{
+		"level":              "error",
+		"message":            "suppressed 3 similar messages",
+		DefaultNameField:     "raft",
+		"peer":               "node4",
+		"suppressed":         float64(3),
+		"suppressed_message": "failed to heartbeat to",
+	}
+
+	for key, value := range want {
+		if summary[key] != value {
+			t.Errorf("expected %q to be %v, got %v", key, value, summary[key])
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if lines := buf.lines(); len(lines) != 6 {
+		t.Errorf("expected no more summaries, got: %v", lines)
+	}
+
+	raft.Error("failed to heartbeat to", "peer", "node1")
+	waitFor(t, func() bool { return len(buf.lines()) == 7 })
+
+	if summary := decodeLines(t, buf)[6]; summary["suppressed"] != float64(1) {
+		t.Errorf("expected the next summary to count 1 message, got %v", summary["suppressed"])
+	}
+}
+
+func TestWithRateLimitLocation(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(
+		zerolog.New(buf),
+		WithLocation(),
+		WithRateLimit(hclog.Error, RateLimit{Rate: 0.001, Burst: 1}),
+		WithRateLimitSummaryInterval(time.Millisecond),
+	)
+
+	_, file, line, _ := runtime.Caller(0)
+	root.Error(messageToLog)
+	root.Error(messageToLog)
+
+	waitFor(t, func() bool { return len(buf.lines()) == 2 })
+
+	msgs := decodeLines(t, buf)
+	assertCaller(t, msgs[0], file, line+1)
+	assertCaller(t, msgs[1], file, line+2)
+}
+
+func TestWithRateLimitSummaryInterval(t *testing.T) {
+	root := NewWithOptions(
+		zerolog.New(io.Discard),
+		WithRateLimitSummaryInterval(time.Second),
+		WithRateLimit(hclog.Error, RateLimit{Rate: 1, Burst: 1}),
+	)
+
+	if interval := root.config.rateLimiter.interval; interval != time.Second {
+		t.Errorf("expected the interval to be %v regardless of the order of the options, got %v", time.Second, interval)
+	}
+
+	if root := New(zerolog.Nop()); root.config.rateLimiter != nil {
+		t.Errorf("expected no rate limiter without limits")
+	}
+}
+
+func TestRateBucket(t *testing.T) {
+	limit := RateLimit{Rate: 2, Burst: 3}
+	start := time.Now()
+	bucket := &rateBucket{tokens: float64(limit.Burst), last: start}
+
+	steps := []struct {
+		after time.Duration
+		want  bool
+	}{
+		{0, true},
+		{0, true},
+		{0, true},
+		{0, false},
+		{250 * time.Millisecond, false},
+		{500 * time.Millisecond, true},
+		{500 * time.Millisecond, false},
+		{10 * time.Second, true},
+		{10 * time.Second, true},
+		{10 * time.Second, true},
+		{10 * time.Second, false},
+	}
+
+	for i, step := range steps {
+		if got := bucket.take(start.Add(step.after), limit); got != step.want {
+			t.Errorf("step %d: expected %v, got %v", i, step.want, got)
+		}
+	}
+}
+
+func TestRateLimiterPrune(t *testing.T) {
+	root := NewWithOptions(zerolog.New(io.Discard), WithRateLimit(hclog.Info, RateLimit{Rate: 1000, Burst: 1}))
+
+	for i := range minRateLimiterPruneSize {
+		root.Info(strconv.Itoa(i))
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	root.Info(messageToLog)
+
+	if buckets := len(root.config.rateLimiter.buckets); buckets != 1 {
+		t.Errorf("expected idle buckets to be pruned, got %d buckets", buckets)
+	}
+}
+
+func TestRateLimiterRace(t *testing.T) {
+	root := NewWithOptions(
+		zerolog.New(io.Discard),
+		WithRateLimit(hclog.Info, RateLimit{Rate: 100, Burst: 5}),
+		WithRateLimitSummaryInterval(time.Millisecond),
+	)
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 100 {
+				root.Named(strconv.Itoa(i%3)).Info(messageToLog, "attempt", j)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
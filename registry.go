@@ -0,0 +1,203 @@
+package hclogzerolog
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"weak"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// minRegistryPruneSize is the number of names the [Registry] holds before it starts to drop the unused ones.
+const minRegistryPruneSize = 64
+
+// Registry records the names of the loggers derived with Named and ResetNamed, along with their
+// levels, implied args and the number of events written, see [WithRegistry].
+//
+// The registry doesn't keep the loggers alive: a name is forgotten once all the loggers
+// carrying it are collected by GC.
+type Registry struct {
+	mu      sync.Mutex
+	entries map[string]*registryEntry
+	pruneAt int
+}
+
+// LoggerInfo describes the loggers of a name recorded by the [Registry].
+type LoggerInfo struct {
+	Name string
+	// Level is the effective level, see [Logger.GetLevel]
+	Level hclog.Level
+	// ImpliedArgs are the implied args of the most recently created logger of the name
+	ImpliedArgs []any
+	// Counts are the numbers of the events written by the loggers of the name, by level,
+	// only the levels with events are present
+	Counts map[hclog.Level]uint64
+	// Loggers is the number of the live loggers of the name
+	Loggers int
+}
+
+// registryEntry holds the loggers of a name. The counts are indexed by [hclog.Level].
+type registryEntry struct {
+	loggers loggerSet
+	counts  [hclog.Off]atomic.Uint64
+}
+
+// NewRegistry creates an empty [Registry].
+func NewRegistry() *Registry {
+	return &Registry{entries: make(map[string]*registryEntry), pruneAt: minRegistryPruneSize}
+}
+
+// WithRegistry records the loggers of the family in the registry.
+// A single registry can be shared by several families.
+//
+//	registry := hclogzerolog.NewRegistry()
+//	logger := hclogzerolog.NewWithOptions(log.Logger, hclogzerolog.WithRegistry(registry))
+//	...
+//	for _, info := range registry.Snapshot() {
+//		fmt.Println(info.Name, info.Level, info.Counts[hclog.Error])
+//	}
+func WithRegistry(registry *Registry) Option {
+	return func(c *config) {
+		c.registry = registry
+	}
+}
+
+// Snapshot returns the names recorded with the loggers still in use, sorted by name.
+func (r *Registry) Snapshot() []LoggerInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	infos := make([]LoggerInfo, 0, len(r.entries))
+
+	for _, name := range slices.Sorted(maps.Keys(r.entries)) {
+		entry := r.entries[name]
+
+		live := entry.loggers.live()
+		if len(live) == 0 {
+			delete(r.entries, name)
+
+			continue
+		}
+
+		latest := live[len(live)-1]
+		info := LoggerInfo{
+			Name:        name,
+			Level:       latest.GetLevel(),
+			ImpliedArgs: slices.Clone(latest.implied),
+			Counts:      make(map[hclog.Level]uint64),
+			Loggers:     len(live),
+		}
+
+		for level := range entry.counts {
+			if count := entry.counts[level].Load(); count > 0 {
+				info.Counts[hclog.Level(level)] = count
+			}
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// latest returns the most recently created live logger of every name recorded for the family of the config,
+// since a registry may be shared by several families.
+func (r *Registry) latest(c *config) map[string]*Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	loggers := make(map[string]*Logger)
+
+	for name, entry := range r.entries {
+		for _, logger := range slices.Backward(entry.loggers.live()) {
+			if logger.config == c {
+				loggers[name] = logger
+
+				break
+			}
+		}
+	}
+
+	return loggers
+}
+
+// register records the logger and returns the entry of its name.
+func (r *Registry) register(logger *Logger) *registryEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[logger.name]
+	if !ok {
+		if len(r.entries) >= r.pruneAt {
+			r.prune()
+		}
+
+		entry = &registryEntry{}
+		r.entries[logger.name] = entry
+	}
+
+	entry.loggers.add(logger)
+
+	return entry
+}
+
+// prune drops the names with no loggers in use. The caller must hold the mutex.
+func (r *Registry) prune() {
+	for name, entry := range r.entries {
+		if len(entry.loggers.live()) == 0 {
+			delete(r.entries, name)
+		}
+	}
+
+	r.pruneAt = max(2*len(r.entries), minRegistryPruneSize)
+}
+
+// count records the event written at the level.
+func (e *registryEntry) count(level hclog.Level) {
+	if int(level) < len(e.counts) {
+		e.counts[level].Add(1)
+	}
+}
+
+// loggerSet tracks the loggers without keeping them alive.
+type loggerSet struct {
+	mu      sync.Mutex
+	loggers []weak.Pointer[Logger]
+}
+
+func (s *loggerSet) add(logger *Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.loggers) == cap(s.loggers) {
+		s.compact()
+	}
+
+	s.loggers = append(s.loggers, weak.Make(logger))
+}
+
+// live returns the loggers still in use, in the order they were created.
+func (s *loggerSet) live() []*Logger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.compact()
+
+	loggers := make([]*Logger, 0, len(s.loggers))
+	for _, pointer := range s.loggers {
+		if logger := pointer.Value(); logger != nil {
+			loggers = append(loggers, logger)
+		}
+	}
+
+	return loggers
+}
+
+// compact drops the loggers collected by GC. The caller must hold the mutex.
+func (s *loggerSet) compact() {
+	s.loggers = slices.DeleteFunc(s.loggers, func(pointer weak.Pointer[Logger]) bool {
+		return pointer.Value() == nil
+	})
+}
@@ -0,0 +1,116 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"io"
+	"maps"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	root := NewWithOptions(zerolog.New(&bytes.Buffer{}), WithLevel(hclog.Info), WithRegistry(registry))
+	raft := root.Named("raft")
+	raftNet := raft.Named("net").With("peer", "node1")
+	memberlist := root.ResetNamed("memberlist")
+
+	root.Info(messageToLog)
+	raft.Info(messageToLog)
+	raft.Warn(messageToLog)
+	raft.Debug(messageToLog)
+	raftNet.Error(messageToLog)
+	raftNet.Error(messageToLog)
+	root.SetLevel(hclog.Warn)
+
+	infos := registry.Snapshot()
+
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name)
+	}
+
+	if want := []string{"memberlist", "raft", "raft.net"}; !slices.Equal(names, want) {
+		t.Fatalf("expected names to be %v, got %v", want, names)
+	}
+
+	raftInfo := infos[1]
+
+	if raftInfo.Level != hclog.Warn {
+		t.Errorf("expected level to be %v, got %v", hclog.Warn, raftInfo.Level)
+	}
+
+	if want := map[hclog.Level]uint64{hclog.Info: 1, hclog.Warn: 1}; !maps.Equal(raftInfo.Counts, want) {
+		t.Errorf("expected counts to be %v, got %v", want, raftInfo.Counts)
+	}
+
+	netInfo := infos[2]
+
+	if want := []any{"peer", "node1"}; !slices.Equal(netInfo.ImpliedArgs, want) {
+		t.Errorf("expected implied args to be %v, got %v", want, netInfo.ImpliedArgs)
+	}
+
+	if netInfo.Loggers != 2 {
+		t.Errorf("expected 2 live loggers, got %d", netInfo.Loggers)
+	}
+
+	if want := map[hclog.Level]uint64{hclog.Error: 2}; !maps.Equal(netInfo.Counts, want) {
+		t.Errorf("expected counts to be %v, got %v", want, netInfo.Counts)
+	}
+
+	runtime.KeepAlive(raftNet)
+	runtime.KeepAlive(memberlist)
+}
+
+func TestRegistryForgetsCollectedLoggers(t *testing.T) {
+	registry := NewRegistry()
+	root := NewWithOptions(zerolog.New(io.Discard), WithRegistry(registry))
+
+	for i := range minRegistryPruneSize {
+		root.Named(strconv.Itoa(i)).Info(messageToLog)
+	}
+
+	runtime.GC()
+
+	raft := root.Named("raft")
+
+	if len(registry.entries) != 1 {
+		t.Errorf("expected unused names to be pruned, got %d names", len(registry.entries))
+	}
+
+	infos := registry.Snapshot()
+	if len(infos) != 1 || infos[0].Name != "raft" {
+		t.Errorf("expected only raft to be recorded, got %v", infos)
+	}
+
+	runtime.KeepAlive(raft)
+	runtime.KeepAlive(root)
+}
+
+func TestRegistryRace(t *testing.T) {
+	registry := NewRegistry()
+	root := NewWithOptions(zerolog.New(io.Discard), WithRegistry(registry))
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 100 {
+				root.Named(strconv.Itoa((i + j) % 5)).Info(messageToLog)
+				_ = registry.Snapshot()
+			}
+		}()
+	}
+
+	wg.Wait()
+}
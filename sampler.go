@@ -0,0 +1,33 @@
+package hclogzerolog
+
+import "github.com/rs/zerolog"
+
+// WithNameSampler samples the messages of the loggers named prefix or descending from it
+// with the [zerolog.Sampler]. If several prefixes match the name, the longest one wins, so
+//
+//	hclogzerolog.WithNameSampler("raft.net", &zerolog.LevelSampler{TraceSampler: &zerolog.BasicSampler{N: 100}}),
+//
+// writes every 100th trace message of "raft.net" and its descendants, but all the messages of "raft".
+//
+// The sampler is resolved once the logger is named and it's shared by all the loggers
+// matching the prefix. The messages are sampled by the wrapped [zerolog.Logger], after all the checks
+// of the [Logger], e.g. [WithRateLimit], and [zerolog.DisableSampling] applies as usual.
+func WithNameSampler(prefix string, sampler zerolog.Sampler) Option {
+	return func(c *config) {
+		if c.nameSamplers == nil {
+			c.nameSamplers = make(map[string]zerolog.Sampler)
+		}
+
+		c.nameSamplers[prefix] = sampler
+	}
+}
+
+// samplerFor returns the sampler of the logger with the given name, if any.
+func (c *config) samplerFor(name string) zerolog.Sampler {
+	prefixes := matchingPrefixes(name, c.nameSeparator, c.nameSamplers)
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	return c.nameSamplers[prefixes[len(prefixes)-1]]
+}
@@ -0,0 +1,63 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithNameSampler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	root := NewWithOptions(
+		zerolog.New(buf),
+		WithNameSampler("raft", &zerolog.BasicSampler{N: 2}),
+		WithNameSampler("raft.net", &zerolog.LevelSampler{TraceSampler: &zerolog.BasicSampler{N: 100}}),
+	)
+
+	tests := []struct {
+		name  string
+		log   func(logger *Logger)
+		wants int
+	}{
+		{"", func(l *Logger) { l.Trace(messageToLog) }, 100},
+		{"raft", func(l *Logger) { l.Trace(messageToLog) }, 50},
+		{"raft.snapshot", func(l *Logger) { l.Info(messageToLog) }, 50},
+		{"raft.net", func(l *Logger) { l.Trace(messageToLog) }, 1},
+		{"raft.net.tcp", func(l *Logger) { l.Trace(messageToLog) }, 1},
+		{"raft.net", func(l *Logger) { l.Info(messageToLog) }, 100},
+		{"rafting", func(l *Logger) { l.Trace(messageToLog) }, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := root.ResetNamed(tt.name).(*Logger)
+
+			buf.Reset()
+
+			for range 100 {
+				tt.log(logger)
+			}
+
+			if lines := strings.Count(buf.String(), "\n"); lines != tt.wants {
+				t.Errorf("expected %d messages to be written, got %d", tt.wants, lines)
+			}
+		})
+	}
+
+	t.Run("Named and With", func(t *testing.T) {
+		logger := root.Named("raft").Named("net").With("peer", "node1")
+
+		buf.Reset()
+
+		for range 100 {
+			logger.Trace(messageToLog, "attempt", 1)
+			logger.Trace(messageToLog, "peer", "node2")
+		}
+
+		if lines := strings.Count(buf.String(), "\n"); lines > 2 {
+			t.Errorf("expected at most 2 messages to be written, got %d", lines)
+		}
+	})
+}
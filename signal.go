@@ -0,0 +1,95 @@
+package hclogzerolog
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// StepLevelOnSignals makes the signals step the level of the logger, as [Logger.SetLevel] does:
+// down lowers it making the logger more verbose, e.g. from [hclog.Info] to [hclog.Debug],
+// up raises it, within [hclog.Trace] - [hclog.Error] range.
+// Every change is written to the logger at [hclog.Info] regardless of its level.
+//
+// The loggers sharing the level with the logger, see [LevelMode], follow the changes,
+// so it's usually called on the root of the family. The returned function stops handling the signals.
+//
+//	stop := logger.StepLevelOnSignals(syscall.SIGUSR1, syscall.SIGUSR2)
+//	defer stop()
+func (l *Logger) StepLevelOnSignals(down, up os.Signal) func() {
+	return l.onSignals([]os.Signal{down, up}, func(sig os.Signal, level hclog.Level) hclog.Level {
+		if sig == down {
+			return max(level-1, hclog.Trace)
+		}
+
+		return min(max(level+1, hclog.Trace), hclog.Error)
+	})
+}
+
+// ToggleLevelOnSignal makes the signal toggle the level of the logger between normal and debug ones,
+// as [Logger.SetLevel] does. If the level is neither, it's set to debug.
+// Every change is written to the logger at [hclog.Info] regardless of its level.
+//
+// The loggers sharing the level with the logger, see [LevelMode], follow the changes,
+// so it's usually called on the root of the family. The returned function stops handling the signal.
+//
+//	stop := logger.ToggleLevelOnSignal(syscall.SIGUSR1, hclog.Info, hclog.Trace)
+//	defer stop()
+func (l *Logger) ToggleLevelOnSignal(sig os.Signal, normal, debug hclog.Level) func() {
+	return l.onSignals([]os.Signal{sig}, func(_ os.Signal, level hclog.Level) hclog.Level {
+		if level == debug {
+			return normal
+		}
+
+		return debug
+	})
+}
+
+// onSignals sets the level returned by next for the current one on every signal
+// until the returned function is called.
+func (l *Logger) onSignals(signals []os.Signal, next func(sig os.Signal, level hclog.Level) hclog.Level) func() {
+	received := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	signal.Notify(received, signals...)
+
+	go func() {
+		defer close(stopped)
+
+		for {
+			select {
+			case sig := <-received:
+				l.changeLevel(sig, next)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+func (l *Logger) changeLevel(sig os.Signal, next func(sig os.Signal, level hclog.Level) hclog.Level) {
+	previous, _ := l.mapping.toHCLog(l.level.get())
+	level := next(sig, previous)
+
+	l.SetLevel(level)
+
+	event := l.logger.WithLevel(l.mapping[hclog.Info]).
+		Str("signal", sig.String()).
+		Str("new_level", level.String()).
+		Str("previous_level", previous.String())
+
+	l.send(event, "Log level changed")
+}
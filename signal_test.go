@@ -0,0 +1,94 @@
+//go:build unix
+
+package hclogzerolog
+
+import (
+	"encoding/json"
+	"syscall"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func sendSignal(t *testing.T, sig syscall.Signal) {
+	t.Helper()
+
+	if err := syscall.Kill(syscall.Getpid(), sig); err != nil {
+		t.Fatalf("failed to send %v: %v", sig, err)
+	}
+}
+
+func TestStepLevelOnSignals(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(zerolog.New(buf), WithLevel(hclog.Info))
+	raft := root.Named("raft")
+
+	stop := root.StepLevelOnSignals(syscall.SIGUSR1, syscall.SIGUSR2)
+	defer stop()
+
+	steps := []struct {
+		sig   syscall.Signal
+		level hclog.Level
+	}{
+		{syscall.SIGUSR1, hclog.Debug},
+		{syscall.SIGUSR1, hclog.Trace},
+		{syscall.SIGUSR1, hclog.Trace},
+		{syscall.SIGUSR2, hclog.Debug},
+		{syscall.SIGUSR2, hclog.Info},
+		{syscall.SIGUSR2, hclog.Warn},
+		{syscall.SIGUSR2, hclog.Error},
+		{syscall.SIGUSR2, hclog.Error},
+	}
+
+	for i, step := range steps {
+		sendSignal(t, step.sig)
+		waitFor(t, func() bool { return len(buf.lines()) == i+1 })
+
+		if raft.GetLevel() != step.level {
+			t.Errorf("step %d: expected level of the named logger to be %v, got %v", i, step.level, raft.GetLevel())
+		}
+	}
+
+	var msg map[string]any
+	if err := json.Unmarshal([]byte(buf.lines()[0]), &msg); err != nil {
+		t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.lines()[0])
+	}
+
+	want := map[string]any{
+		"level":          "info",
+		"new_level":      "debug",
+		"message":        "Log level changed",
+		"signal":         syscall.SIGUSR1.String(),
+		"previous_level": "info",
+	}
+
+	for key, value := range want {
+		if msg[key] != value {
+			t.Errorf("expected %q to be %v, got %v", key, value, msg[key])
+		}
+	}
+}
+
+func TestToggleLevelOnSignal(t *testing.T) {
+	buf := &syncBuffer{}
+	root := NewWithOptions(zerolog.New(buf), WithLevel(hclog.Warn))
+
+	stop := root.ToggleLevelOnSignal(syscall.SIGUSR1, hclog.Info, hclog.Trace)
+
+	for i, want := range []hclog.Level{hclog.Trace, hclog.Info, hclog.Trace} {
+		sendSignal(t, syscall.SIGUSR1)
+		waitFor(t, func() bool { return len(buf.lines()) == i+1 })
+
+		if root.GetLevel() != want {
+			t.Errorf("step %d: expected level to be %v, got %v", i, want, root.GetLevel())
+		}
+	}
+
+	stop()
+	stop()
+
+	if root.GetLevel() != hclog.Trace {
+		t.Errorf("expected level to stay %v after stop, got %v", hclog.Trace, root.GetLevel())
+	}
+}
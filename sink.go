@@ -0,0 +1,75 @@
+package hclogzerolog
+
+import (
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+// SinkAdapter is an [hclog.SinkAdapter] writing the messages intercepted
+// by an [hclog.InterceptLogger] into [zerolog.Logger].
+//
+// Messages are written exactly as [Logger] writes them: the [hclog] name goes to the name field,
+// the levels are mapped with the [LevelMapping] and the args become the fields.
+// Every intercepted name gets its own logger, so the options set per name, like [WithNameLevel],
+// apply to the messages of that name.
+// Messages below the level of the adapter are dropped.
+type SinkAdapter struct {
+	logger *Logger
+
+	mu    sync.RWMutex
+	named map[string]*Logger
+}
+
+// NewSinkAdapter creates an instance of [SinkAdapter] writing into provided [zerolog.Logger]
+// configured with the given options.
+//
+//	interceptLogger := hclog.NewInterceptLogger(nil)
+//	interceptLogger.RegisterSink(hclogzerolog.NewSinkAdapter(log.Logger))
+func NewSinkAdapter(logger zerolog.Logger, opts ...Option) *SinkAdapter {
+	return &SinkAdapter{logger: NewWithOptions(logger, opts...), named: make(map[string]*Logger)}
+}
+
+// Accept implements [hclog.SinkAdapter].
+// An empty name keeps the name the adapter was created with, see [WithName].
+func (s *SinkAdapter) Accept(name string, level hclog.Level, msg string, args ...any) {
+	if level == hclog.Off {
+		return
+	}
+
+	logger := s.logger
+	if name != "" {
+		logger = s.namedLogger(name)
+	}
+
+	if _, ok := logger.mapping.toZerolog(level); !ok {
+		logger.unknownLevel(level)
+
+		return
+	}
+
+	logger.log(0, level, msg, args)
+}
+
+// namedLogger returns the logger of the intercepted name, like [HCLogWriter.namedLogger] does.
+// The name is the full one, so it replaces the name of the adapter.
+func (s *SinkAdapter) namedLogger(name string) *Logger {
+	s.mu.RLock()
+	logger, ok := s.named[name]
+	s.mu.RUnlock()
+
+	if ok {
+		return logger
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if logger, ok = s.named[name]; !ok {
+		logger = s.logger.derive(name, s.logger.implied)
+		s.named[name] = logger
+	}
+
+	return logger
+}
@@ -0,0 +1,129 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestSinkAdapter(t *testing.T) {
+	var _ hclog.SinkAdapter = NewSinkAdapter(zerolog.Nop())
+
+	t.Run("writes intercepted messages", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		interceptLogger := hclog.NewInterceptLogger(&hclog.LoggerOptions{Output: &bytes.Buffer{}, Level: hclog.Off})
+		interceptLogger.RegisterSink(NewSinkAdapter(zerolog.New(buf), WithNameField("subsystem")))
+
+		interceptLogger.Named("raft").With("peer", "node1").Warn(messageToLog, customFieldName, customFieldValue)
+
+		var msg map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+		}
+
+		want := map[string]any{
+			"level":         "warn",
+			"message":       messageToLog,
+			"subsystem":     "raft",
+			"peer":          "node1",
+			customFieldName: customFieldValue,
+		}
+
+		for key, value := range want {
+			if msg[key] != value {
+				t.Errorf("expected field %q to be %v, got %v", key, value, msg[key])
+			}
+		}
+	})
+
+	t.Run("maps levels", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		sink := NewSinkAdapter(
+			zerolog.New(buf).Level(zerolog.InfoLevel),
+			WithLevelMapping(LevelMapping{hclog.Error: zerolog.WarnLevel}),
+		)
+
+		sink.Accept("raft", hclog.Debug, messageToLog)
+		sink.Accept("raft", hclog.Off, messageToLog)
+
+		if buf.Len() != 0 {
+			t.Errorf("expected messages to be dropped, got: %s", buf.String())
+		}
+
+		sink.Accept("raft", hclog.Error, messageToLog)
+
+		msg := &message{}
+		if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+		}
+
+		if msg.Level != "warn" {
+			t.Errorf("expected level to be mapped to %q, got %q", "warn", msg.Level)
+		}
+	})
+
+	t.Run("keeps own name for unnamed messages", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		sink := NewSinkAdapter(zerolog.New(buf), WithName("app"))
+
+		sink.Accept("", hclog.Info, messageToLog)
+
+		msg := &message{}
+		if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+		}
+
+		if msg.HCLogName != "app" {
+			t.Errorf("expected name to be %q, got %q", "app", msg.HCLogName)
+		}
+
+		buf.Reset()
+		sink.Accept("raft", hclog.Info, messageToLog)
+
+		if occurrences := keyOccurrences(t, buf.Bytes()); occurrences[DefaultNameField] != 1 {
+			t.Errorf("expected name field to be written once, got: %s", buf.String())
+		}
+	})
+
+	t.Run("applies options of intercepted names", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		sink := NewSinkAdapter(zerolog.New(buf), WithLevel(hclog.Info), WithNameLevel("raft.net", hclog.Error))
+
+		sink.Accept("raft.net", hclog.Warn, messageToLog)
+
+		if buf.Len() != 0 {
+			t.Errorf("expected the name level to drop the message, got: %s", buf.String())
+		}
+
+		sink.Accept("memberlist", hclog.Warn, messageToLog)
+		sink.Accept("raft.net", hclog.Error, messageToLog)
+
+		if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+			t.Errorf("expected 2 messages, got: %s", buf.String())
+		}
+
+		if len(sink.named) != 2 {
+			t.Errorf("expected the loggers of 2 names to be cached, got %d", len(sink.named))
+		}
+	})
+
+	t.Run("reports unknown levels", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		sink := NewSinkAdapter(zerolog.New(buf))
+
+		sink.Accept("raft", hclog.Level(999), messageToLog)
+
+		msg := &message{}
+		if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+		}
+
+		if msg.Level != "error" || msg.Message != "Unknown log level: unknown" {
+			t.Errorf("expected unknown level error, got %+v", msg)
+		}
+	})
+}
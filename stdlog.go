@@ -0,0 +1,176 @@
+package hclogzerolog
+
+import (
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// logTimestampRegexp matches characters commonly found in timestamps at the beginning of the line.
+var logTimestampRegexp = regexp.MustCompile(`^[\d\s:/.+\-TZ]*`)
+
+// subsystemRegexp matches the "subsystem: " prefix of the line, like in "memberlist: Stream connection".
+var subsystemRegexp = regexp.MustCompile(`^([A-Za-z][\w.\-]*): `)
+
+// trailingKeyValueRegexp matches the key=value pair at the end of the line, the value can be quoted.
+var trailingKeyValueRegexp = regexp.MustCompile(`\s+([A-Za-z_][\w.\-]*)=("(?:[^"\\]|\\.)*"|[^\s"]*)$`)
+
+// levelPrefixes are the level prefixes [hclog] infers levels from.
+var levelPrefixes = []struct {
+	prefix string
+	level  hclog.Level
+}{
+	{"[TRACE]", hclog.Trace},
+	{"[DEBUG]", hclog.Debug},
+	{"[INFO]", hclog.Info},
+	{"[WARN]", hclog.Warn},
+	{"[ERROR]", hclog.Error},
+	{"[ERR]", hclog.Error},
+}
+
+// stdWriter shims the data written by [log.Logger] into the [Logger].
+type stdWriter struct {
+	logger                   *Logger
+	inferLevels              bool
+	inferLevelsWithTimestamp bool
+	forceLevel               hclog.Level
+
+	mu         sync.Mutex
+	subsystems map[string]*Logger
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	line := strings.TrimRight(string(p), " \t\n")
+	level := hclog.Info
+
+	switch {
+	case w.forceLevel != hclog.NoLevel:
+		_, line = pickLevel(line)
+		level = w.forceLevel
+	case w.inferLevels:
+		if w.inferLevelsWithTimestamp {
+			line = trimTimestamp(line)
+		}
+
+		level, line = pickLevel(line)
+	}
+
+	if level == hclog.Off {
+		return len(p), nil
+	}
+
+	if _, ok := w.logger.mapping.toZerolog(level); !ok {
+		level = hclog.Info
+	}
+
+	logger := w.logger
+
+	if w.logger.config.stdSubsystems {
+		if match := subsystemRegexp.FindStringSubmatch(line); match != nil {
+			logger = w.subsystem(match[1])
+			line = line[len(match[0]):]
+		}
+	}
+
+	var args []any
+	if w.logger.config.stdKeyValues {
+		line, args = trimKeyValues(line)
+	}
+
+	logger.log(stdlibLogDepth(), level, line, args)
+
+	return len(p), nil
+}
+
+// subsystem returns the sublogger named after the subsystem.
+func (w *stdWriter) subsystem(name string) *Logger {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.subsystems == nil {
+		w.subsystems = make(map[string]*Logger)
+	}
+
+	logger, ok := w.subsystems[name]
+	if !ok {
+		logger = w.logger.named(name)
+		w.subsystems[name] = logger
+	}
+
+	return logger
+}
+
+// pickLevel detects the level of the line by the prefix and strips it off.
+// Lines without the prefix are considered to be at [hclog.Info].
+func pickLevel(line string) (hclog.Level, string) {
+	for _, p := range levelPrefixes {
+		if strings.HasPrefix(line, p.prefix) {
+			return p.level, strings.TrimSpace(line[len(p.prefix):])
+		}
+	}
+
+	return hclog.Info, line
+}
+
+func trimTimestamp(line string) string {
+	return line[len(logTimestampRegexp.FindString(line)):]
+}
+
+// trimKeyValues strips the trailing key=value pairs off the line and returns them as args,
+// in the order they appear in the line. Quoted values are unquoted.
+func trimKeyValues(line string) (string, []any) {
+	var pairs [][]string
+
+	for {
+		match := trailingKeyValueRegexp.FindStringSubmatchIndex(line)
+		if match == nil {
+			break
+		}
+
+		key, value := line[match[2]:match[3]], line[match[4]:match[5]]
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+
+		pairs = append(pairs, []string{key, value})
+		line = line[:match[0]]
+	}
+
+	args := make([]any, 0, 2*len(pairs))
+	for i := len(pairs) - 1; i >= 0; i-- {
+		args = append(args, pairs[i][0], pairs[i][1])
+	}
+
+	return line, args
+}
+
+// stdlibLogDepth returns the number of the frames of the [log] package calling the [stdWriter.Write],
+// e.g. [log.Logger.Printf] and the internals of [log.Logger], so they are skipped when the caller
+// location is reported. It's 0 when the writer is used directly.
+func stdlibLogDepth() int {
+	const maxDepth = 8
+
+	var pcs [maxDepth]uintptr
+
+	// skip runtime.Callers, stdlibLogDepth and stdWriter.Write
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	depth := 0
+
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "log.") {
+			return depth
+		}
+
+		depth++
+
+		if !more {
+			return depth
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package hclogzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/rs/zerolog"
+)
+
+func TestStandardWriterOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *hclog.StandardLoggerOptions
+		line    string
+		level   string
+		message string
+	}{
+		{"no options", nil, "[DEBUG] raw line\n", "info", "[DEBUG] raw line"},
+		{"infer trace", &hclog.StandardLoggerOptions{InferLevels: true}, "[TRACE] line\n", "trace", "line"},
+		{"infer debug", &hclog.StandardLoggerOptions{InferLevels: true}, "[DEBUG] line\n", "debug", "line"},
+		{"infer info", &hclog.StandardLoggerOptions{InferLevels: true}, "[INFO]  line\n", "info", "line"},
+		{"infer warn", &hclog.StandardLoggerOptions{InferLevels: true}, "[WARN] line \n", "warn", "line"},
+		{"infer error", &hclog.StandardLoggerOptions{InferLevels: true}, "[ERROR] line\n", "error", "line"},
+		{"infer err", &hclog.StandardLoggerOptions{InferLevels: true}, "[ERR] line\n", "error", "line"},
+		{"infer without prefix", &hclog.StandardLoggerOptions{InferLevels: true}, "line\n", "info", "line"},
+		{
+			"infer ignores timestamp",
+			&hclog.StandardLoggerOptions{InferLevels: true},
+			"2025/03/26 08:47:49 [WARN] line\n", "info", "2025/03/26 08:47:49 [WARN] line",
+		},
+		{
+			"infer with timestamp",
+			&hclog.StandardLoggerOptions{InferLevels: true, InferLevelsWithTimestamp: true},
+			"2025/03/26 08:47:49 [WARN] line\n", "warn", "line",
+		},
+		{
+			"infer with RFC3339 timestamp",
+			&hclog.StandardLoggerOptions{InferLevels: true, InferLevelsWithTimestamp: true},
+			"2025-03-26T08:47:49.123+01:00 [ERR] line\n", "error", "line",
+		},
+		{
+			"timestamp is ignored without infer",
+			&hclog.StandardLoggerOptions{InferLevelsWithTimestamp: true},
+			"2025/03/26 08:47:49 [WARN] line\n", "info", "2025/03/26 08:47:49 [WARN] line",
+		},
+		{"force level", &hclog.StandardLoggerOptions{ForceLevel: hclog.Warn}, "line\n", "warn", "line"},
+		{
+			"force level strips prefix",
+			&hclog.StandardLoggerOptions{ForceLevel: hclog.Error, InferLevels: true},
+			"[DEBUG] line\n", "error", "line",
+		},
+		{"unknown forced level", &hclog.StandardLoggerOptions{ForceLevel: hclog.Level(999)}, "line\n", "info", "line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			hclogLogger := New(zerolog.New(buf))
+
+			if _, err := hclogLogger.StandardWriter(tt.opts).Write([]byte(tt.line)); err != nil {
+				t.Fatalf("expected no error while writing, got: %v", err)
+			}
+
+			msg := &message{}
+			if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+				t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+			}
+
+			if msg.Level != tt.level || msg.Message != tt.message {
+				t.Errorf("expected (%q, %q), got (%q, %q)", tt.level, tt.message, msg.Level, msg.Message)
+			}
+		})
+	}
+
+	t.Run("forced off level drops lines", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		writer := New(zerolog.New(buf)).StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Off})
+
+		if _, err := writer.Write([]byte("line\n")); err != nil {
+			t.Fatalf("expected no error while writing, got: %v", err)
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("expected nothing to be written, got: %s", buf.String())
+		}
+	})
+}
+
+func TestStandardLoggerLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	hclogLogger := New(zerolog.New(buf))
+	stdLogger := hclogLogger.StandardLogger(nil)
+
+	hclogLogger.SetLevel(hclog.Error)
+	stdLogger.Print("dropped")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected the line to be filtered by the level, got: %s", buf.String())
+	}
+
+	hclogLogger.SetLevel(hclog.Info)
+	stdLogger.Print(messageToLog)
+
+	msg := &message{}
+	if err := json.Unmarshal(buf.Bytes(), msg); err != nil {
+		t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+	}
+
+	if msg.Level != "info" || msg.Message != messageToLog {
+		t.Errorf("expected (%q, %q), got (%q, %q)", "info", messageToLog, msg.Level, msg.Message)
+	}
+}
+
+func TestStandardLoggerOptions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	hclogLogger := New(zerolog.New(buf).Level(zerolog.InfoLevel)).Named("memberlist").With("peer", "node1")
+
+	stdLogger := hclogLogger.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true, InferLevelsWithTimestamp: true})
+	stdLogger.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	stdLogger.Printf("[DEBUG] memberlist: dropped")
+	stdLogger.Printf("[WARN] memberlist: Was able to connect to node2 over TCP but UDP probes failed")
+
+	var msg map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+		t.Fatalf("Expected a single valid JSON line, got: %s", buf.String())
+	}
+
+	want := map[string]any{
+		"level":          "warn",
+		"message":        "memberlist: Was able to connect to node2 over TCP but UDP probes failed",
+		DefaultNameField: "memberlist",
+		"peer":           "node1",
+	}
+
+	for key, value := range want {
+		if msg[key] != value {
+			t.Errorf("expected field %q to be %v, got %v", key, value, msg[key])
+		}
+	}
+}
+
+func TestStandardWriterSubsystems(t *testing.T) {
+	opts := &hclog.StandardLoggerOptions{InferLevels: true}
+
+	tests := []struct {
+		name    string
+		options []Option
+		line    string
+		want    map[string]any
+	}{
+		{
+			"disabled by default",
+			nil,
+			"[DEBUG] memberlist: Stream connection from=10.0.0.2:7946\n",
+			map[string]any{DefaultNameField: "raft", "message": "memberlist: Stream connection from=10.0.0.2:7946"},
+		},
+		{
+			"subsystem",
+			[]Option{WithStandardSubsystems()},
+			"[DEBUG] memberlist: Stream connection from=10.0.0.2:7946\n",
+			map[string]any{
+				"level":          "debug",
+				DefaultNameField: "raft.memberlist",
+				"message":        "Stream connection from=10.0.0.2:7946",
+			},
+		},
+		{
+			"subsystem keeps colons in the message",
+			[]Option{WithStandardSubsystems()},
+			"[ERR] yamux: Failed to read header: EOF\n",
+			map[string]any{DefaultNameField: "raft.yamux", "message": "Failed to read header: EOF"},
+		},
+		{
+			"no subsystem",
+			[]Option{WithStandardSubsystems()},
+			"[WARN] Failed to read header\n",
+			map[string]any{DefaultNameField: "raft", "message": "Failed to read header"},
+		},
+		{
+			"key values",
+			[]Option{WithStandardKeyValues()},
+			"[DEBUG] memberlist: Stream connection from=10.0.0.2:7946\n",
+			map[string]any{
+				DefaultNameField: "raft",
+				"message":        "memberlist: Stream connection",
+				"from":           "10.0.0.2:7946",
+			},
+		},
+		{
+			"subsystem and key values",
+			[]Option{WithStandardSubsystems(), WithStandardKeyValues()},
+			`[WARN] serf: Event failed node=node2 error="connection refused" attempt=3` + "\n",
+			map[string]any{
+				DefaultNameField: "raft.serf",
+				"message":        "Event failed",
+				"node":           "node2",
+				"error":          "connection refused",
+				"attempt":        "3",
+			},
+		},
+		{
+			"key values in the middle are kept",
+			[]Option{WithStandardKeyValues()},
+			"[INFO] node=node2 joined the cluster\n",
+			map[string]any{DefaultNameField: "raft", "message": "node=node2 joined the cluster"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			hclogLogger := NewWithOptions(zerolog.New(buf), append(tt.options, WithName("raft"))...)
+
+			if _, err := hclogLogger.StandardWriter(opts).Write([]byte(tt.line)); err != nil {
+				t.Fatalf("expected no error while writing, got: %v", err)
+			}
+
+			var msg map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+				t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+			}
+
+			for key, value := range tt.want {
+				if msg[key] != value {
+					t.Errorf("expected field %q to be %v, got %v", key, value, msg[key])
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,13 @@
 package hclogzerolog
 
 import (
+	"fmt"
 	"io"
 	"log"
+	"runtime"
+	"slices"
+	"sort"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/rs/zerolog"
@@ -16,13 +21,35 @@ import (
 // Logger name acts like a prefix for the log message.
 // On the other hand, [zerolog] operates key/value pairs to add context to messages.
 // So, we convert the [hclog] logger name to key/value context pair for [zerolog]
-// This is a default, can be overridden while creating wrapper with the [NewWithCustomNameField]
+// This is a default, can be overridden while creating wrapper with the [NewWithCustomNameField] or [WithNameField]
+//
+// Unnamed loggers don't write the field at all, and every key, including this one,
+// is written once per message, the latest value set wins.
 const DefaultNameField = "hclog_name"
 
+// callerSkipFrameCount is the number of frames to skip from [Logger.write] to the user of the [Logger]:
+// the write itself, the log and the method of the [Logger] called by the user, see [Logger.log].
+const callerSkipFrameCount = 3
+
 type Logger struct {
-	logger    zerolog.Logger
-	nameField string
-	name      string
+	// base is the wrapped logger as it was provided, without the fields added by the wrapper
+	base zerolog.Logger
+	// logger is the base with the name and implied args added
+	logger  zerolog.Logger
+	config  *config
+	name    string
+	implied []any
+	level   *levelHolder
+	// mapping is the level mapping resolved for the name
+	mapping LevelMapping
+	// resolved is the level rule resolved for the name, see [WithNameLevel]
+	resolved atomic.Pointer[resolvedLevel]
+	// entry is the entry of the name in the [Registry], if any
+	entry *registryEntry
+	// levelRules are the rules applying to the name, see [WithLevelRules]
+	levelRules []*LevelRule
+	// sampler is the sampler of the name, see [WithNameSampler]
+	sampler zerolog.Sampler
 }
 
 // New creates an instance of [Logger] wrapping provided [zerolog.Logger].
@@ -37,90 +64,88 @@ type Logger struct {
 // See:
 //   - https://pkg.go.dev/github.com/hashicorp/raft#Config
 func New(logger zerolog.Logger) *Logger {
-	return &Logger{
-		logger:    logger.With().Str(DefaultNameField, "").Logger(),
-		nameField: DefaultNameField,
-		name:      "",
-	}
+	return NewWithOptions(logger)
 }
 
 // NewWithCustomNameField — does exactly the same as [New] but with the ability to set field (key)
 // the [hclog.Logger] name will be written to.
+//
+// It's a shortcut for [NewWithOptions] with [WithNameField].
 func NewWithCustomNameField(logger zerolog.Logger, nameField string) *Logger {
-	return &Logger{
-		logger:    logger.With().Str(nameField, "").Logger(),
-		nameField: nameField,
-		name:      "",
-	}
+	return NewWithOptions(logger, WithNameField(nameField))
+}
+
+// NewWithLevelMode — does exactly the same as [New] but with the ability to choose
+// how the level is shared with the derived loggers, see [LevelMode].
+//
+// The level of the provided [zerolog.Logger] becomes the initial level of the family,
+// later it's changed with [Logger.SetLevel] only.
+//
+// It's a shortcut for [NewWithOptions] with [WithLevelMode].
+func NewWithLevelMode(logger zerolog.Logger, mode LevelMode) *Logger {
+	return NewWithOptions(logger, WithLevelMode(mode))
 }
 
 func (l *Logger) Log(level hclog.Level, msg string, args ...any) {
-	switch level {
-	case hclog.Trace:
-		l.logger.Trace().Fields(args).Msg(msg)
-	case hclog.Debug:
-		l.logger.Debug().Fields(args).Msg(msg)
-	case hclog.Info:
-		l.logger.Info().Fields(args).Msg(msg)
-	case hclog.Warn:
-		l.logger.Warn().Fields(args).Msg(msg)
-	case hclog.Error:
-		l.logger.Error().Fields(args).Msg(msg)
-	case hclog.NoLevel:
-		l.logger.Log().Fields(args).Msg(msg)
-	case hclog.Off:
-		// no-op
-	default:
-		l.logger.Error().Msgf("Unknown log level: %s", level)
+	if level == hclog.Off {
+		return
 	}
+
+	if _, ok := l.mapping.toZerolog(level); !ok {
+		l.unknownLevel(level)
+
+		return
+	}
+
+	l.log(0, level, msg, args)
 }
 
 func (l *Logger) Trace(format string, args ...any) {
-	l.logger.Trace().Fields(args).Msg(format)
+	l.log(0, hclog.Trace, format, args)
 }
 
 func (l *Logger) Debug(format string, args ...any) {
-	l.logger.Debug().Fields(args).Msg(format)
+	l.log(0, hclog.Debug, format, args)
 }
 
 func (l *Logger) Info(format string, args ...any) {
-	l.logger.Info().Fields(args).Msg(format)
+	l.log(0, hclog.Info, format, args)
 }
 
 func (l *Logger) Warn(format string, args ...any) {
-	l.logger.Warn().Fields(args).Msg(format)
+	l.log(0, hclog.Warn, format, args)
 }
 
 func (l *Logger) Error(format string, args ...any) {
-	l.logger.Error().Fields(args).Msg(format)
+	l.log(0, hclog.Error, format, args)
 }
 
 func (l *Logger) IsTrace() bool {
-	return l.logger.GetLevel() == zerolog.TraceLevel
+	return l.enabled(l.mapping[hclog.Trace])
 }
 
 func (l *Logger) IsDebug() bool {
-	return l.logger.GetLevel() == zerolog.DebugLevel
+	return l.enabled(l.mapping[hclog.Debug])
 }
 
 func (l *Logger) IsInfo() bool {
-	return l.logger.GetLevel() == zerolog.InfoLevel
+	return l.enabled(l.mapping[hclog.Info])
 }
 
 func (l *Logger) IsWarn() bool {
-	return l.logger.GetLevel() == zerolog.WarnLevel
+	return l.enabled(l.mapping[hclog.Warn])
 }
 
 func (l *Logger) IsError() bool {
-	return l.logger.GetLevel() == zerolog.ErrorLevel
+	return l.enabled(l.mapping[hclog.Error])
 }
 
 func (l *Logger) ImpliedArgs() []any {
-	return nil
+	return l.implied
 }
 
 func (l *Logger) With(args ...any) hclog.Logger {
-	return &Logger{l.logger.With().Fields(args).Logger(), l.nameField, l.name}
+	return l.with(args)
 }
 
 func (l *Logger) Name() string {
@@ -128,68 +153,443 @@ func (l *Logger) Name() string {
 }
 
 func (l *Logger) Named(name string) hclog.Logger {
+	return l.named(name)
+}
+
+func (l *Logger) ResetNamed(name string) hclog.Logger {
+	return l.derive(name, l.implied)
+}
+
+// SetLevel updates the level of the logger and, depending on the [LevelMode],
+// of the related loggers. It's safe to call it concurrently with logging.
+// The level of a logger matched by a [Logger.SetNameLevel] rule is taken from the rule instead.
+func (l *Logger) SetLevel(level hclog.Level) {
+	mapped, ok := l.mapping.toZerolog(level)
+	if !ok {
+		l.unknownLevel(level)
+
+		return
+	}
+
+	l.level.set(mapped)
+}
+
+// GetLevel returns the effective threshold of the logger,
+// that is the most restrictive of its own level, or the one set for its name with [Logger.SetNameLevel],
+// and [zerolog.GlobalLevel],
+// mapped back to [hclog] level.
+// Like in [hclog], it's [hclog.NoLevel] if the logger is set to it and the global level lets every event through.
+// [zerolog.NoLevel] global level drops all the leveled events, so it's reported as [hclog.Off].
+func (l *Logger) GetLevel() hclog.Level {
+	threshold := l.threshold()
+	level := max(threshold, zerolog.GlobalLevel())
+
+	switch {
+	case threshold == permissiveLevel && level <= zerolog.TraceLevel:
+		return hclog.NoLevel
+	case level == zerolog.NoLevel:
+		return hclog.Off
+	}
+
+	mapped, ok := l.mapping.toHCLog(level)
+	if !ok {
+		l.unknownLevel(level)
+	}
+
+	return mapped
+}
+
+// StandardLogger returns [log.Logger] writing into the [Logger], see [Logger.StandardWriter].
+func (l *Logger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(l.StandardWriter(opts), "", 0)
+}
+
+// StandardWriter returns [io.Writer] turning every written line into an event of the [Logger],
+// carrying its name and implied args.
+//
+// With InferLevels option set, the level is taken from the line prefix like [DEBUG] or [ERR],
+// which is stripped, the lines without the prefix are written at [hclog.Info].
+// InferLevelsWithTimestamp allows a timestamp before the prefix, it's stripped as well.
+// ForceLevel writes all the lines at the given level, stripping the prefixes if any.
+// Without the options the lines are written at [hclog.Info], like in [hclog], so they are filtered
+// by the level of the [Logger] as well.
+func (l *Logger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	if opts == nil {
+		opts = &hclog.StandardLoggerOptions{}
+	}
+
+	return &stdWriter{
+		logger:                   l,
+		inferLevels:              opts.InferLevels,
+		inferLevelsWithTimestamp: opts.InferLevelsWithTimestamp,
+		forceLevel:               opts.ForceLevel,
+	}
+}
+
+// log checks the message and writes it with write.
+// The level is rewritten by the [LevelRule]s first, so everything downstream,
+// including the sinks, sees the rewritten one.
+//
+// The depth is the number of frames between the method called by the user of the [Logger]
+// and the caller of the log. It's used to skip the frames of the wrapper when the caller
+// location is reported, either by [WithLocation] or by the [zerolog.Context.Caller] of the wrapped logger.
+func (l *Logger) log(depth int, level hclog.Level, msg string, args []any) {
+	original := level
+
+	if l.levelRules != nil {
+		level = l.applyLevelRules(level, msg, args)
+		if level == hclog.Off {
+			return
+		}
+	}
+
+	if l.config.sinks != nil {
+		l.config.sinks.accept(l.name, level, msg, l.implied, args)
+	}
+
+	if !l.enabled(l.mapping[level]) {
+		if l.config.recorder != nil && (l.config.exclude == nil || !l.config.exclude(level, msg, args...)) {
+			l.config.recorder.record(l, l.location(depth), level, original, msg, args)
+		}
+
+		return
+	}
+
+	if l.config.exclude != nil && l.config.exclude(level, msg, args...) {
+		return
+	}
+
+	// the messages written later on behalf of this one, e.g. the summaries, are reported at its call site
+	var caller string
+	if l.config.rateLimiter != nil || l.config.collapser != nil {
+		caller = l.location(depth)
+	}
+
+	if l.config.rateLimiter != nil && !l.config.rateLimiter.allow(l, caller, level, original, msg, args) {
+		return
+	}
+
+	if l.config.collapser != nil {
+		ended, ok := l.config.collapser.collapse(l, caller, level, original, msg, args)
+		if !ok {
+			return
+		}
+
+		ended.write()
+	}
+
+	if l.entry != nil {
+		l.entry.count(level)
+	}
+
+	if l.config.recorder != nil && l.config.recorder.trigger(level, msg, args...) {
+		l.config.recorder.flush(l)
+	}
+
+	l.write(depth, caller, level, original, msg, args)
+}
+
+// write writes the event on top of the logger context.
+// Keys of args replace the same keys of the name and implied args,
+// so that every key is written once and the last written value wins.
+// Keys of the fields [zerolog] writes on its own are prefixed with an underscore, see [escapeReservedKeys].
+// The original level is the one the message was logged at, before the [LevelRule]s.
+// The caller is the location of the message captured with [Logger.location], if any,
+// otherwise the location is taken from the stack, see [Logger.log].
+func (l *Logger) write(depth int, caller string, level, original hclog.Level, msg string, args []any) {
+	logger := &l.logger
+	args = escapeReservedKeys(args)
+
+	if implied := escapeReservedKeys(l.implied); overridesContext(args, l.config.nameField, l.name, implied) {
+		args = uniqueArgs(args)
+
+		name := l.name
+		if hasKey(args, l.config.nameField) {
+			name = ""
+		}
+
+		ctx := l.context(name, withoutKeys(implied, args))
+		logger = &ctx
+	}
+
+	event := logger.WithLevel(l.mapping[level])
+	if l.config.levelField != "" && original != hclog.NoLevel && !hasKey(args, l.config.levelField) {
+		event = event.Str(l.config.levelField, original.String())
+	}
+
+	event = event.Fields(args).CallerSkipFrame(callerSkipFrameCount + depth + l.config.locationOffset)
+
+	switch {
+	case caller != "":
+		event = event.Str(zerolog.CallerFieldName, caller)
+	case l.config.includeLocation:
+		event = event.Caller()
+	}
+
+	if l.config.mutex != nil {
+		l.config.mutex.Lock()
+		defer l.config.mutex.Unlock()
+	}
+
+	event.Msg(msg)
+}
+
+// location returns the location of the caller of the [Logger], if [WithLocation] is set, formatted like
+// [zerolog.Event.Caller] does. It's for the messages written later on behalf of the message being logged,
+// e.g. the summaries of [WithRateLimit], so it must be called by [Logger.log] with its depth.
+func (l *Logger) location(depth int) string {
+	if !l.config.includeLocation {
+		return ""
+	}
+
+	_, file, line, ok := runtime.Caller(callerSkipFrameCount + depth + l.config.locationOffset)
+	if !ok {
+		return ""
+	}
+
+	return zerolog.CallerMarshalFunc(file, line)
+}
+
+// send writes the event built by the wrapper itself, bypassing the level, holding the mutex, if any.
+func (l *Logger) send(event *zerolog.Event, msg string) {
+	if l.config.mutex != nil {
+		l.config.mutex.Lock()
+		defer l.config.mutex.Unlock()
+	}
+
+	event.Msg(msg)
+}
+
+// with creates a sublogger with the args added to the implied ones.
+func (l *Logger) with(args []any) *Logger {
+	return l.derive(l.name, mergeArgs(l.implied, args))
+}
+
+// named creates a sublogger with the name descending from the current one.
+func (l *Logger) named(name string) *Logger {
 	var newName string
 	if l.name == "" {
 		newName = name
 	} else {
-		newName = l.name + "." + name
+		newName = l.name + l.config.nameSeparator + name
 	}
 
-	return &Logger{l.logger.With().Str(l.nameField, newName).Logger(), l.nameField, newName}
+	return l.derive(newName, l.implied)
 }
 
-func (l *Logger) ResetNamed(name string) hclog.Logger {
-	return &Logger{l.logger.With().Str(l.nameField, name).Logger(), l.nameField, name}
+// derive creates a sublogger with the given name and implied args.
+func (l *Logger) derive(name string, implied []any) *Logger {
+	mapping := l.config.levelMappingFor(name)
+
+	derived := &Logger{
+		base:       l.base,
+		config:     l.config,
+		name:       name,
+		implied:    implied,
+		level:      l.level.derive(),
+		mapping:    mapping,
+		levelRules: l.config.levelRulesFor(name, mapping),
+		sampler:    l.config.samplerFor(name),
+	}
+	derived.logger = derived.context(name, implied)
+	derived.resolveNameLevel()
+	derived.register()
+
+	return derived
 }
 
-func (l *Logger) SetLevel(level hclog.Level) {
-	switch level {
-	case hclog.Trace:
-		l.logger = l.logger.Level(zerolog.TraceLevel)
-	case hclog.Debug:
-		l.logger = l.logger.Level(zerolog.DebugLevel)
-	case hclog.Info:
-		l.logger = l.logger.Level(zerolog.InfoLevel)
-	case hclog.Warn:
-		l.logger = l.logger.Level(zerolog.WarnLevel)
-	case hclog.Error:
-		l.logger = l.logger.Level(zerolog.ErrorLevel)
-	case hclog.Off:
-		l.logger = l.logger.Level(zerolog.Disabled)
-	case hclog.NoLevel:
-		l.logger = l.logger.Level(zerolog.NoLevel)
-	default:
-		l.logger.Error().Msgf("Unknown log level: %s", level)
+// register records the logger in the [Registry], if any, unless it's unnamed.
+func (l *Logger) register() {
+	if l.name != "" && l.config.registry != nil {
+		l.entry = l.config.registry.register(l)
 	}
 }
 
-func (l *Logger) GetLevel() hclog.Level {
-	switch l.logger.GetLevel() {
-	case zerolog.TraceLevel:
-		return hclog.Trace
-	case zerolog.DebugLevel:
-		return hclog.Debug
-	case zerolog.InfoLevel:
-		return hclog.Info
-	case zerolog.WarnLevel:
-		return hclog.Warn
-	case zerolog.ErrorLevel, zerolog.FatalLevel, zerolog.PanicLevel:
-		return hclog.Error
-	case zerolog.Disabled:
-		return hclog.Off
-	case zerolog.NoLevel:
-		return hclog.NoLevel
-	default:
-		l.logger.Error().Msgf("Unknown log level: %s", l.logger.GetLevel())
+// context builds the zerolog logger carrying the name and implied args
+// on top of the base one, sampled with the sampler of the logger, if any.
+// The name takes precedence over an implied arg stored under the name field.
+func (l *Logger) context(name string, implied []any) zerolog.Logger {
+	ctx := l.base.With()
 
-		return hclog.NoLevel
+	if name != "" {
+		ctx = ctx.Str(l.config.nameField, name)
+		implied = withoutKeys(implied, []any{l.config.nameField, name})
 	}
+
+	logger := ctx.Fields(escapeReservedKeys(implied)).Logger()
+	if l.sampler != nil {
+		logger = logger.Sample(l.sampler)
+	}
+
+	return logger
+}
+
+// threshold returns the level of the rule matching the name, see [WithNameLevel], or the level of the logger.
+// Like in [hclog], [hclog.NoLevel] threshold, mapped to [zerolog.NoLevel], lets every event through,
+// while [zerolog.Disabled] one, same as [hclog.Off], lets none of the leveled events through.
+func (l *Logger) threshold() zerolog.Level {
+	level, ok := l.nameLevel()
+	if !ok {
+		level = l.level.get()
+	}
+
+	if level == zerolog.NoLevel {
+		return permissiveLevel
+	}
+
+	return level
+}
+
+// effectiveLevel returns the level events are filtered by.
+// Like a [zerolog.Logger] does, it drops every event below the threshold of the logger or below
+// [zerolog.GlobalLevel], so it's the greater of the two.
+func (l *Logger) effectiveLevel() zerolog.Level {
+	return max(l.threshold(), zerolog.GlobalLevel())
 }
 
-func (l *Logger) StandardLogger(_ *hclog.StandardLoggerOptions) *log.Logger {
-	return log.New(l.logger, "", 0)
+func (l *Logger) unknownLevel(level fmt.Stringer) {
+	l.log(1, hclog.Error, fmt.Sprintf("Unknown log level: %s", level), nil)
 }
 
-func (l *Logger) StandardWriter(_ *hclog.StandardLoggerOptions) io.Writer {
-	return l.logger
+// enabled reports whether an event of the given level would be emitted.
+func (l *Logger) enabled(level zerolog.Level) bool {
+	return level >= l.effectiveLevel()
+}
+
+// mergeArgs merges new key/value pairs into the implied ones exactly like
+// [hclog] does in With: a repeated key keeps the latest value, the result is
+// sorted by key and a dangling value is stored under [hclog.MissingKey].
+// Keys which are not strings are converted with [fmt.Sprint].
+func mergeArgs(implied, args []any) []any {
+	var extra any
+
+	if len(args)%2 != 0 {
+		extra = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	values := make(map[string]any, (len(implied)+len(args))/2)
+	keys := make([]string, 0, (len(implied)+len(args))/2)
+
+	for _, pairs := range [][]any{implied, args} {
+		for i := 0; i < len(pairs); i += 2 {
+			key := argKey(pairs[i])
+			if _, exists := values[key]; !exists {
+				keys = append(keys, key)
+			}
+
+			values[key] = pairs[i+1]
+		}
+	}
+
+	sort.Strings(keys)
+
+	merged := make([]any, 0, 2*len(keys)+2)
+	for _, key := range keys {
+		merged = append(merged, key, values[key])
+	}
+
+	if extra != nil {
+		merged = append(merged, hclog.MissingKey, extra)
+	}
+
+	return merged
+}
+
+func argKey(key any) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(key)
+}
+
+// overridesContext reports whether args repeat one of their own keys,
+// the name field of a named logger or a key of the implied args.
+func overridesContext(args []any, nameField, name string, implied []any) bool {
+	for i := 0; i+1 < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok {
+			continue
+		}
+
+		if (name != "" && key == nameField) || hasKey(implied, key) || hasKey(args[:i], key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reservedKeyPrefix is prepended to the keys of args clashing with the fields [zerolog] writes on its own.
+const reservedKeyPrefix = "_"
+
+// isReservedKey reports whether [zerolog] writes the field of the key on its own.
+func isReservedKey(key string) bool {
+	return key == zerolog.LevelFieldName || key == zerolog.MessageFieldName ||
+		key == zerolog.TimestampFieldName || key == zerolog.CallerFieldName
+}
+
+// escapeReservedKeys returns args with the keys reserved by [zerolog] prefixed with [reservedKeyPrefix],
+// so that they don't repeat the level, the message, the timestamp or the caller. It returns args as is if
+// there are no such keys.
+func escapeReservedKeys(args []any) []any {
+	var escaped []any
+
+	for i := 0; i+1 < len(args); i += 2 {
+		if key, ok := args[i].(string); ok && isReservedKey(key) {
+			if escaped == nil {
+				escaped = slices.Clone(args)
+			}
+
+			escaped[i] = reservedKeyPrefix + key
+		}
+	}
+
+	if escaped == nil {
+		return args
+	}
+
+	return escaped
+}
+
+// uniqueArgs drops the key/value pairs which keys are repeated later in args.
+func uniqueArgs(args []any) []any {
+	unique := make([]any, 0, len(args))
+
+	for i := 0; i+1 < len(args); i += 2 {
+		if key, ok := args[i].(string); ok && hasKey(args[i+2:], key) {
+			continue
+		}
+
+		unique = append(unique, args[i], args[i+1])
+	}
+
+	return unique
+}
+
+// withoutKeys returns the key/value pairs of args which keys are not in the
+// key/value pairs of other.
+func withoutKeys(args, other []any) []any {
+	filtered := make([]any, 0, len(args))
+
+	for i := 0; i+1 < len(args); i += 2 {
+		if key, ok := args[i].(string); ok && hasKey(other, key) {
+			continue
+		}
+
+		filtered = append(filtered, args[i], args[i+1])
+	}
+
+	return filtered
+}
+
+func hasKey(args []any, key string) bool {
+	for i := 0; i+1 < len(args); i += 2 {
+		if args[i] == key {
+			return true
+		}
+	}
+
+	return false
 }
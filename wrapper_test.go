@@ -3,6 +3,7 @@ package hclogzerolog
 import (
 	"bytes"
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
@@ -33,8 +34,8 @@ func TestNew(t *testing.T) {
 	buf := &bytes.Buffer{}
 	hclogLogger := New(zerolog.New(buf))
 
-	if hclogLogger.nameField != DefaultNameField {
-		t.Errorf("expected nameField to be %q, got %q", DefaultNameField, hclogLogger.nameField)
+	if hclogLogger.config.nameField != DefaultNameField {
+		t.Errorf("expected nameField to be %q, got %q", DefaultNameField, hclogLogger.config.nameField)
 	}
 
 	if hclogLogger.name != "" {
@@ -48,8 +49,8 @@ func TestNewWithCustomNameField(t *testing.T) {
 
 	hclogLogger := NewWithCustomNameField(zerolog.New(buf), customNameField)
 
-	if hclogLogger.nameField != customNameField {
-		t.Errorf("expected nameField to be %q, got %q", customNameField, hclogLogger.nameField)
+	if hclogLogger.config.nameField != customNameField {
+		t.Errorf("expected nameField to be %q, got %q", customNameField, hclogLogger.config.nameField)
 	}
 
 	if hclogLogger.name != "" {
@@ -263,8 +264,8 @@ func TestIsTrace(t *testing.T) {
 		}
 	})
 
-	t.Run("returns false when logger level is not Trace", func(t *testing.T) {
-		logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.InfoLevel)
+	t.Run("returns false when logger level is above Trace", func(t *testing.T) {
+		logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.DebugLevel)
 		hclogLogger := New(logger)
 
 		if hclogLogger.IsTrace() {
@@ -283,7 +284,7 @@ func TestIsDebug(t *testing.T) {
 		}
 	})
 
-	t.Run("returns false when logger level is not Debug", func(t *testing.T) {
+	t.Run("returns false when logger level is above Debug", func(t *testing.T) {
 		logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.InfoLevel)
 		hclogLogger := New(logger)
 
@@ -303,8 +304,8 @@ func TestIsInfo(t *testing.T) {
 		}
 	})
 
-	t.Run("returns false when logger level is not Info", func(t *testing.T) {
-		logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.DebugLevel)
+	t.Run("returns false when logger level is above Info", func(t *testing.T) {
+		logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.WarnLevel)
 		hclogLogger := New(logger)
 
 		if hclogLogger.IsInfo() {
@@ -323,8 +324,8 @@ func TestIsWarn(t *testing.T) {
 		}
 	})
 
-	t.Run("returns false when logger level is not Warn", func(t *testing.T) {
-		logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.InfoLevel)
+	t.Run("returns false when logger level is above Warn", func(t *testing.T) {
+		logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.ErrorLevel)
 		hclogLogger := New(logger)
 
 		if hclogLogger.IsWarn() {
@@ -343,8 +344,8 @@ func TestIsError(t *testing.T) {
 		}
 	})
 
-	t.Run("returns false when logger level is not Error", func(t *testing.T) {
-		logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.WarnLevel)
+	t.Run("returns false when logger level is above Error", func(t *testing.T) {
+		logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.Disabled)
 		hclogLogger := New(logger)
 
 		if hclogLogger.IsError() {
@@ -364,6 +365,70 @@ func TestImpliedArgs(t *testing.T) {
 			t.Errorf("expected impliedArgs to be nil, got %v", impliedArgs)
 		}
 	})
+
+	t.Run("returns args in the same order as hclog", func(t *testing.T) {
+		calls := [][]any{
+			{"peer", "node1"},
+			{"term", 1, "addr", "127.0.0.1"},
+			{"peer", "node2", "dangling"},
+			{"index", 42},
+		}
+
+		var hclogLogger hclog.Logger = New(zerolog.New(&bytes.Buffer{}))
+
+		var reference hclog.Logger = hclog.New(&hclog.LoggerOptions{Output: &bytes.Buffer{}})
+
+		for _, args := range calls {
+			hclogLogger = hclogLogger.With(args...)
+			reference = reference.With(args...)
+
+			if !reflect.DeepEqual(hclogLogger.ImpliedArgs(), reference.ImpliedArgs()) {
+				t.Errorf("expected implied args to be %v, got %v", reference.ImpliedArgs(), hclogLogger.ImpliedArgs())
+			}
+		}
+	})
+
+	t.Run("converts non-string keys", func(t *testing.T) {
+		hclogLogger := New(zerolog.New(&bytes.Buffer{})).With(1, "one")
+
+		want := []any{"1", "one"}
+		if !reflect.DeepEqual(hclogLogger.ImpliedArgs(), want) {
+			t.Errorf("expected implied args to be %v, got %v", want, hclogLogger.ImpliedArgs())
+		}
+	})
+
+	t.Run("keeps implied args in derived loggers", func(t *testing.T) {
+		hclogLogger := New(zerolog.New(&bytes.Buffer{})).With("peer", "node1")
+		want := []any{"peer", "node1"}
+
+		derived := map[string]hclog.Logger{
+			"Named":      hclogLogger.Named("raft"),
+			"ResetNamed": hclogLogger.ResetNamed("raft"),
+		}
+
+		for name, logger := range derived {
+			if !reflect.DeepEqual(logger.ImpliedArgs(), want) {
+				t.Errorf("expected %s logger implied args to be %v, got %v", name, want, logger.ImpliedArgs())
+			}
+		}
+
+		hclogLogger.SetLevel(hclog.Debug)
+
+		if !reflect.DeepEqual(hclogLogger.ImpliedArgs(), want) {
+			t.Errorf("expected implied args after SetLevel to be %v, got %v", want, hclogLogger.ImpliedArgs())
+		}
+	})
+
+	t.Run("does not modify the parent logger", func(t *testing.T) {
+		parent := New(zerolog.New(&bytes.Buffer{})).With("peer", "node1")
+
+		parent.With("term", 2)
+
+		want := []any{"peer", "node1"}
+		if !reflect.DeepEqual(parent.ImpliedArgs(), want) {
+			t.Errorf("expected parent implied args to be %v, got %v", want, parent.ImpliedArgs())
+		}
+	})
 }
 
 func TestWith(t *testing.T) {
@@ -446,6 +511,124 @@ func TestResetNamed(t *testing.T) {
 	})
 }
 
+func TestUniqueKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		log   func(hclogLogger *Logger)
+		wants map[string]any
+	}{
+		{
+			name: "unnamed logger has no name field",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.Info(messageToLog)
+			},
+			wants: map[string]any{DefaultNameField: nil},
+		},
+		{
+			name: "repeated With keys",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.With("term", 1).With("term", 2).Info(messageToLog)
+			},
+			wants: map[string]any{"term": float64(2)},
+		},
+		{
+			name: "repeated Named calls",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.Named("raft").Named("net").Info(messageToLog)
+			},
+			wants: map[string]any{DefaultNameField: "raft.net"},
+		},
+		{
+			name: "ResetNamed after Named",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.Named("raft").ResetNamed("memberlist").Info(messageToLog)
+			},
+			wants: map[string]any{DefaultNameField: "memberlist"},
+		},
+		{
+			name: "ResetNamed to empty name",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.Named("raft").ResetNamed("").Info(messageToLog)
+			},
+			wants: map[string]any{DefaultNameField: nil},
+		},
+		{
+			name: "name takes precedence over With",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.With(DefaultNameField, "implied").Named("raft").Info(messageToLog)
+			},
+			wants: map[string]any{DefaultNameField: "raft"},
+		},
+		{
+			name: "args override With",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.With("term", 1, "peer", "node1").Info(messageToLog, "term", 2)
+			},
+			wants: map[string]any{"term": float64(2), "peer": "node1"},
+		},
+		{
+			name: "args override name",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.Named("raft").Info(messageToLog, DefaultNameField, "arg")
+			},
+			wants: map[string]any{DefaultNameField: "arg"},
+		},
+		{
+			name: "repeated args",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.Log(hclog.Info, messageToLog, "term", 1, "term", 2)
+			},
+			wants: map[string]any{"term": float64(2)},
+		},
+		{
+			name: "keys of zerolog fields",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.With("message", "implied").Info(messageToLog, "level", "arg", "time", 1, "caller", "arg")
+			},
+			wants: map[string]any{
+				"message": messageToLog, "level": "info", "_message": "implied", "_level": "arg", "_time": float64(1),
+			},
+		},
+		{
+			name: "keys of zerolog fields override the escaped ones",
+			log: func(hclogLogger *Logger) {
+				hclogLogger.With("_message", "implied").Info(messageToLog, "message", "arg", "_level", 1, "level", 2)
+			},
+			wants: map[string]any{"_message": "arg", "_level": float64(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			hclogLogger := NewWithOptions(zerolog.New(buf).With().Timestamp().Logger(), WithLocation())
+
+			tt.log(hclogLogger)
+
+			occurrences := keyOccurrences(t, buf.Bytes())
+			for key, count := range occurrences {
+				if count > 1 {
+					t.Errorf("expected key %q to be written once, got %d times in %s", key, count, buf.String())
+				}
+			}
+
+			var msg map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+				t.Fatalf("Expected log output to be a valid JSON, got: %s", buf.String())
+			}
+
+			for key, want := range tt.wants {
+				got, exists := msg[key]
+				if want == nil && exists {
+					t.Errorf("expected no %q field, got %v", key, got)
+				} else if want != nil && got != want {
+					t.Errorf("expected field %q to be %v, got %v", key, want, got)
+				}
+			}
+		})
+	}
+}
+
 func TestSetLevel(t *testing.T) {
 	t.Run("sets the logger level correctly", func(t *testing.T) {
 		tests := []struct {
@@ -468,8 +651,8 @@ func TestSetLevel(t *testing.T) {
 
 				hclogLogger.SetLevel(tt.hclogLevel)
 
-				if hclogLogger.logger.GetLevel() != tt.expectedLevel {
-					t.Errorf("expected logger level to be %v, got %v", tt.expectedLevel, hclogLogger.logger.GetLevel())
+				if hclogLogger.level.get() != tt.expectedLevel {
+					t.Errorf("expected logger level to be %v, got %v", tt.expectedLevel, hclogLogger.level.get())
 				}
 			})
 		}
@@ -535,7 +718,9 @@ func TestGetLevel(t *testing.T) {
 		logger := zerolog.New(buf)
 		hclogLogger := New(logger)
 
-		hclogLogger.logger = hclogLogger.logger.Level(zerolog.Level(-2))
+		hclogLogger.level.set(zerolog.Level(-2))
+
+		setGlobalLevel(t, zerolog.Level(-2))
 
 		level := hclogLogger.GetLevel()
 
@@ -560,6 +745,140 @@ func TestGetLevel(t *testing.T) {
 	})
 }
 
+func TestLevelMatrix(t *testing.T) {
+	type expectation struct {
+		level   hclog.Level
+		isTrace bool
+		isDebug bool
+		isInfo  bool
+		isWarn  bool
+		isError bool
+	}
+
+	expectations := map[zerolog.Level]expectation{
+		zerolog.TraceLevel: {hclog.Trace, true, true, true, true, true},
+		zerolog.DebugLevel: {hclog.Debug, false, true, true, true, true},
+		zerolog.InfoLevel:  {hclog.Info, false, false, true, true, true},
+		zerolog.WarnLevel:  {hclog.Warn, false, false, false, true, true},
+		zerolog.ErrorLevel: {hclog.Error, false, false, false, false, true},
+		zerolog.FatalLevel: {hclog.Error, false, false, false, false, false},
+		zerolog.PanicLevel: {hclog.Error, false, false, false, false, false},
+		zerolog.NoLevel:    {hclog.NoLevel, true, true, true, true, true},
+		zerolog.Disabled:   {hclog.Off, false, false, false, false, false},
+	}
+
+	levels := []zerolog.Level{
+		zerolog.TraceLevel,
+		zerolog.DebugLevel,
+		zerolog.InfoLevel,
+		zerolog.WarnLevel,
+		zerolog.ErrorLevel,
+		zerolog.FatalLevel,
+		zerolog.PanicLevel,
+		zerolog.NoLevel,
+		zerolog.Disabled,
+	}
+
+	check := func(t *testing.T, hclogLogger *Logger, want expectation) {
+		t.Helper()
+
+		got := expectation{
+			level:   hclogLogger.GetLevel(),
+			isTrace: hclogLogger.IsTrace(),
+			isDebug: hclogLogger.IsDebug(),
+			isInfo:  hclogLogger.IsInfo(),
+			isWarn:  hclogLogger.IsWarn(),
+			isError: hclogLogger.IsError(),
+		}
+
+		if got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	}
+
+	for _, loggerLevel := range levels {
+		for _, globalLevel := range levels {
+			t.Run(loggerLevel.String()+"/global_"+globalLevel.String(), func(t *testing.T) {
+				setGlobalLevel(t, globalLevel)
+
+				hclogLogger := New(zerolog.New(&bytes.Buffer{}).Level(loggerLevel))
+
+				want := expectations[loggerLevel]
+
+				switch {
+				case globalLevel == zerolog.NoLevel:
+					// zerolog drops all the leveled events below its global NoLevel
+					want = expectations[zerolog.Disabled]
+				case loggerLevel == zerolog.NoLevel:
+					if globalLevel > zerolog.TraceLevel {
+						want = expectations[globalLevel]
+					}
+				case globalLevel > loggerLevel:
+					want = expectations[globalLevel]
+				}
+
+				check(t, hclogLogger, want)
+			})
+		}
+	}
+
+	hclogLevels := []struct {
+		hclogLevel   hclog.Level
+		zerologLevel zerolog.Level
+	}{
+		{hclog.Trace, zerolog.TraceLevel},
+		{hclog.Debug, zerolog.DebugLevel},
+		{hclog.Info, zerolog.InfoLevel},
+		{hclog.Warn, zerolog.WarnLevel},
+		{hclog.Error, zerolog.ErrorLevel},
+		{hclog.NoLevel, zerolog.NoLevel},
+		{hclog.Off, zerolog.Disabled},
+	}
+
+	for _, tt := range hclogLevels {
+		t.Run("SetLevel/"+tt.hclogLevel.String(), func(t *testing.T) {
+			hclogLogger := New(zerolog.New(&bytes.Buffer{}))
+
+			hclogLogger.SetLevel(tt.hclogLevel)
+
+			check(t, hclogLogger, expectations[tt.zerologLevel])
+		})
+	}
+}
+
+func TestLevelEmitsWhatIsReported(t *testing.T) {
+	emitters := []struct {
+		name    string
+		enabled func(*Logger) bool
+		emit    func(*Logger)
+	}{
+		{"trace", (*Logger).IsTrace, func(l *Logger) { l.Trace(messageToLog) }},
+		{"debug", (*Logger).IsDebug, func(l *Logger) { l.Debug(messageToLog) }},
+		{"info", (*Logger).IsInfo, func(l *Logger) { l.Info(messageToLog) }},
+		{"warn", (*Logger).IsWarn, func(l *Logger) { l.Warn(messageToLog) }},
+		{"error", (*Logger).IsError, func(l *Logger) { l.Error(messageToLog) }},
+	}
+
+	for _, globalLevel := range []zerolog.Level{zerolog.TraceLevel, zerolog.InfoLevel, zerolog.Disabled} {
+		for _, loggerLevel := range []zerolog.Level{zerolog.TraceLevel, zerolog.WarnLevel, zerolog.NoLevel} {
+			for _, emitter := range emitters {
+				t.Run(globalLevel.String()+"/"+loggerLevel.String()+"/"+emitter.name, func(t *testing.T) {
+					setGlobalLevel(t, globalLevel)
+
+					buf := &bytes.Buffer{}
+					hclogLogger := New(zerolog.New(buf).Level(loggerLevel))
+
+					emitter.emit(hclogLogger)
+
+					if emitted := buf.Len() > 0; emitted != emitter.enabled(hclogLogger) {
+						t.Errorf("Is%s returned %v, but the event was emitted: %v", emitter.name, !emitted, emitted)
+					}
+				})
+			}
+		}
+	}
+}
+
 func TestStandardLogger(t *testing.T) {
 	t.Run("returns a standard logger that writes to zerolog", func(t *testing.T) {
 		buf := &bytes.Buffer{}
@@ -607,3 +926,39 @@ func TestStandardWriter(t *testing.T) {
 		}
 	})
 }
+
+func setGlobalLevel(t *testing.T, level zerolog.Level) {
+	t.Helper()
+
+	previous := zerolog.GlobalLevel()
+
+	zerolog.SetGlobalLevel(level)
+	t.Cleanup(func() { zerolog.SetGlobalLevel(previous) })
+}
+
+func keyOccurrences(t *testing.T, data []byte) map[string]int {
+	t.Helper()
+
+	occurrences := map[string]int{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		t.Fatalf("Expected log output to be a valid JSON, got: %s", data)
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", data)
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			t.Fatalf("Expected log output to be a valid JSON, got: %s", data)
+		}
+
+		occurrences[key.(string)]++
+	}
+
+	return occurrences
+}